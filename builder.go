@@ -1,5 +1,7 @@
 package structconfig
 
+import "github.com/berquerant/structconfig/internal"
+
 func NewBuilder[T any](sc *StructConfig[T], merger *Merger[T]) *Builder[T] {
 	return &Builder[T]{
 		sc:     sc,
@@ -20,41 +22,123 @@ func (b *Builder[T]) Add(f func(*StructConfig[T]) (*T, error)) *Builder[T] {
 }
 
 // Build generates a Config in order from the generators added earlier and override them accordingly.
+//
+// A value overrides if the From methods of [StructConfig] in the generator actually set it,
+// e.g. the command-line flag is given or the environment variable exists, even if it is the default value.
+// Otherwise a value overrides if it is not the default value, see [Merger.Merge].
+//
+// Then it sets "default" tag values that reference other fields to the fields that no generators set,
+// see [StructConfig.ResolveDefaultRefs].
+// It fails if required fields are missing or some fields are invalid,
+// see [StructConfig.CheckRequired] and [StructConfig.Validate].
+// A required field is missing if no generators set it and it has no "default" tag,
+// so an explicit zero value like --replicas=0 is not missing.
+// The violations name the sources of the values, see [Builder.BuildWithProvenance].
 func (b *Builder[T]) Build() (*T, error) {
+	r, _, err := b.build()
+	return r, err
+}
+
+// BuildWithProvenance is [Builder.Build] that also returns the origins of the field values.
+//
+// The source of a value set by a generator is the one recorded by the From methods of [StructConfig] called in it,
+// e.g. [SourceEnv] for [StructConfig.FromEnv], "layerN" for the N-th generator (0-based) that records nothing.
+// The fields that no generators set are from [SourceDefault] with "default" tag value.
+//
+// A generator that records nothing, e.g. one that builds the value without the From methods,
+// is known to set a field only if the value is not the default value.
+// So the value of it that equals the default value is from [SourceDefault] even if it is given explicitly.
+func (b *Builder[T]) BuildWithProvenance() (*T, Provenance, error) {
+	return b.build()
+}
+
+func (b *Builder[T]) build() (*T, Provenance, error) {
+	// compare with the default values expanded the same as sc
+	merger := b.merger.WithExpand(b.sc.expander())
+
 	configList := make([]*T, len(b.chain))
+	recorders := make([]*recorder, len(b.chain))
 	for i, c := range b.chain {
-		x, err := b.newConfig(c)
+		recorders[i] = newRecorder()
+		x, err := b.newConfig(merger, c, recorders[i])
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		configList[i] = x
 	}
 
 	r, err := b.newDefault()
 	if err != nil {
-		return nil, err
+		return nil, nil, err
 	}
-	for _, c := range configList {
-		x, err := b.merger.Merge(*r, *c)
+	provenance := Provenance{}
+	typ, err := b.sc.newType()
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, f := range typ.Fields() {
+		if _, ok := f.Tag().Name(); ok {
+			provenance[f.Name()] = defaultOrigin(f)
+		}
+	}
+	// the fields that some generator set for merging, resolving the references and checking required fields:
+	// the recorded origins, or the values other than the defaults if the generator records nothing
+	given := map[string]bool{}
+	for i, c := range configList {
+		isSet := func(f StructField) bool {
+			_, ok := recorders[i].origins[f.Name()]
+			return ok
+		}
+		x, err := merger.MergeSetFunc(*r, *c, isSet, func(f StructField, d MergeDecision) {
+			if d == MergeRight {
+				if recorders[i].source == "" || isSet(f) {
+					given[f.Name()] = true
+				}
+				provenance[f.Name()] = layerOrigin(i, recorders[i], f, c)
+			}
+		})
 		if err != nil {
-			return nil, err
+			return nil, nil, err
 		}
 		r = &x
 	}
 
-	return r, nil
+	isGiven := func(f StructField) bool { return given[f.Name()] }
+	resolved, err := b.sc.resolveDefaultRefs(r, isGiven)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, f := range typ.Fields() {
+		if v, ok := resolved[f.Name()]; ok {
+			provenance[f.Name()].Raw = internal.RedactValue(f, v)
+		}
+	}
+
+	if err := b.sc.checkRequired(r, isGiven); err != nil {
+		return nil, nil, err
+	}
+	if err := b.sc.validate(r, provenance); err != nil {
+		return nil, nil, err
+	}
+	return r, provenance, nil
 }
 
-func (b *Builder[T]) newConfig(f func(*StructConfig[T]) (*T, error)) (*T, error) {
+func (b *Builder[T]) newConfig(
+	merger *internal.Merger[T],
+	f func(*StructConfig[T]) (*T, error),
+	rec *recorder,
+) (*T, error) {
 	d, err := b.newDefault()
 	if err != nil {
 		return nil, err
 	}
-	v, err := f(b.sc)
+	sc := *b.sc
+	sc.recorder = rec
+	v, err := f(&sc)
 	if err != nil {
 		return nil, err
 	}
-	r, err := b.merger.Merge(*d, *v)
+	r, err := merger.Merge(*d, *v)
 	if err != nil {
 		return nil, err
 	}
@@ -75,3 +75,44 @@ func ExampleBuilder() {
 	fmt.Println(c.Default, c.Env, c.Flag, c.File, c.Override)
 	// Output: default from_env from_flag from_file overrided
 }
+
+func ExampleBuilder_BuildWithProvenance() {
+	type T struct {
+		Name  string `name:"name" default:"default"`
+		Debug bool   `name:"debug"`
+		Level int    `name:"level" default:"1"`
+	}
+
+	c, provenance, err := structconfig.NewBuilder[T](
+		structconfig.New[T](structconfig.WithEnviron([]string{"NAME=from_env", "LEVEL=2"})),
+		structconfig.NewMerger[T](),
+	).
+		Add(func(sc *structconfig.StructConfig[T]) (*T, error) {
+			var t T
+			if err := sc.FromEnv(&t); err != nil {
+				return nil, err
+			}
+			return &t, nil
+		}).
+		Add(func(sc *structconfig.StructConfig[T]) (*T, error) {
+			var t T
+			if err := sc.FromDefault(&t); err != nil {
+				return nil, err
+			}
+			t.Debug = true
+			return &t, nil
+		}).
+		BuildWithProvenance()
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(c.Name, c.Debug, c.Level)
+	fmt.Println(provenance["Name"])
+	fmt.Println(provenance["Debug"])
+	fmt.Println(provenance["Level"])
+	// Output:
+	// from_env true 2
+	// env NAME="from_env"
+	// layer1 "true"
+	// env LEVEL="2"
+}
@@ -5,15 +5,16 @@ import (
 	"errors"
 	"fmt"
 	"reflect"
+	"slices"
 
 	"github.com/berquerant/structconfig"
 )
 
 func ExampleMerger() {
 	type T struct {
-		I                 int    `name:"i" default:"1"`
-		S                 string `name:"s" default:"s"`
-		II                []int  `name:"ii" default:"[1]"`
+		I                 int     `name:"i" default:"1"`
+		S                 string  `name:"s" default:"s"`
+		II                [][]int `name:"ii" default:"[[1]]"`
 		IgnoreWithoutName int
 	}
 
@@ -21,7 +22,7 @@ func ExampleMerger() {
 		if s.Name() != "II" {
 			return errors.New("unexpected field name")
 		}
-		var xs []int
+		var xs [][]int
 		if err := json.Unmarshal([]byte(v), &xs); err != nil {
 			return err
 		}
@@ -30,12 +31,12 @@ func ExampleMerger() {
 	}
 
 	eq := func(a, b any) (bool, error) {
-		// expect only []int because int and string are supported by structconfig
-		xs, ok := a.([]int)
+		// expect only [][]int because int, string and []int are supported by structconfig
+		xs, ok := a.([][]int)
 		if !ok {
 			return false, nil
 		}
-		ys, ok := b.([]int)
+		ys, ok := b.([][]int)
 		if !ok {
 			return false, nil
 		}
@@ -43,7 +44,7 @@ func ExampleMerger() {
 			return false, nil
 		}
 		for i, x := range xs {
-			if x != ys[i] {
+			if !slices.Equal(x, ys[i]) {
 				return false, nil
 			}
 		}
@@ -58,17 +59,17 @@ func ExampleMerger() {
 		T{
 			I:  100,
 			S:  "s", // default
-			II: []int{100},
+			II: [][]int{{100}},
 		},
 		T{
 			I:  1, // default
 			S:  "win",
-			II: []int{1}, // default
+			II: [][]int{{1}}, // default
 		},
 	)
 	if err != nil {
 		panic(err)
 	}
 	fmt.Println(got.I, got.S, got.II)
-	// Output: 100 win [100]
+	// Output: 100 win [[100]]
 }
@@ -0,0 +1,46 @@
+package structconfig_test
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/berquerant/structconfig"
+	"github.com/spf13/pflag"
+)
+
+func ExampleRegister() {
+	type T struct {
+		Endpoint url.URL `name:"endpoint" default:"http://localhost:8080"`
+		Proxy    url.URL `name:"proxy" default:"http://localhost:3128"`
+	}
+
+	registry := structconfig.NewRegistry()
+	if err := structconfig.Register(
+		registry,
+		func(s string) (url.URL, error) {
+			u, err := url.Parse(s)
+			if err != nil {
+				return url.URL{}, err
+			}
+			return *u, nil
+		},
+		func(left, right url.URL) bool {
+			return left.String() == right.String()
+		},
+	); err != nil {
+		panic(err)
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c, err := structconfig.NewConfigWithMerge(
+		structconfig.New[T](structconfig.WithRegistry(registry)),
+		structconfig.NewMerger[T](structconfig.WithRegistry(registry)),
+		fs,
+		structconfig.WithArguments([]string{"--endpoint", "https://example.com/api"}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(c.Endpoint.String(), c.Proxy.String())
+	// Output: https://example.com/api http://localhost:3128
+}
@@ -3,7 +3,9 @@ package structconfig_test
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"path/filepath"
 	"reflect"
 	"sort"
 	"strings"
@@ -158,3 +160,238 @@ func ExampleStructConfig_FromFlags_prefix() {
 	// bool_value,string_value
 	// true sv
 }
+
+func ExampleStructConfig_FromFlags_nested() {
+	type Pool struct {
+		Size int `name:"size" default:"10"`
+	}
+	type DB struct {
+		Host string `name:"host" default:"localhost"`
+		Pool Pool   `name:"pool"`
+	}
+	type T struct {
+		DB DB `name:"db"`
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	sc := structconfig.New[T]()
+
+	if err := sc.SetFlags(fs); err != nil {
+		panic(err)
+	}
+
+	flagNames := []string{}
+	fs.VisitAll(func(f *pflag.Flag) {
+		flagNames = append(flagNames, f.Name)
+	})
+
+	if err := fs.Parse([]string{"--db.pool.size", "20"}); err != nil {
+		panic(err)
+	}
+
+	var got T
+	if err := sc.FromFlags(&got, fs); err != nil {
+		panic(err)
+	}
+
+	sort.Strings(flagNames)
+	fmt.Println(strings.Join(flagNames, ","))
+	fmt.Println(got.DB.Host, got.DB.Pool.Size)
+	// Output:
+	// db.host,db.pool.size
+	// localhost 20
+}
+
+func ExampleStructConfig_FromYAML() {
+	type DB struct {
+		Host string `name:"host" default:"localhost"`
+		Port int    `name:"port" default:"5432"`
+	}
+	type T struct {
+		DB    DB       `name:"db"`
+		Hosts []string `name:"hosts"`
+	}
+
+	sc := structconfig.New[T]()
+	var got T
+	if err := sc.FromYAML(&got, strings.NewReader(`db:
+  host: db.example.com
+hosts:
+  - a
+  - b
+`)); err != nil {
+		panic(err)
+	}
+	fmt.Println(got.DB.Host, got.DB.Port, got.Hosts)
+
+	err := sc.FromYAML(&got, strings.NewReader(`db:
+  port: x
+`))
+	fmt.Println(err)
+	// Output:
+	// db.example.com 5432 [a b]
+	// StructConfig: line 2, column 9: DB.Port (db.port): strconv.ParseInt: parsing "x": invalid syntax
+}
+
+func ExampleStructConfig_FromDotenv() {
+	type T struct {
+		Host string `name:"host" default:"localhost"`
+		Port int    `name:"port" default:"80"`
+		URL  string `name:"url"`
+	}
+
+	sc := structconfig.New[T]()
+	var got T
+	if err := sc.FromDotenv(&got, strings.NewReader(`# comment
+export HOST=example.com
+URL="http://${HOST}:8080"
+`)); err != nil {
+		panic(err)
+	}
+	_, ok := os.LookupEnv("HOST")
+	fmt.Println(got.Host, got.Port, got.URL, ok)
+	// Output: example.com 80 http://example.com:8080 false
+}
+
+func ExampleWithEnvLookup() {
+	type T struct {
+		S string `name:"string_value"`
+		N int    `name:"int_value" default:"10"`
+	}
+
+	env := map[string]string{
+		"STRING_VALUE": "str",
+	}
+	sc := structconfig.New[T](structconfig.WithEnvLookup(func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}))
+	var got T
+	if err := sc.FromEnv(&got); err != nil {
+		panic(err)
+	}
+	fmt.Println(got.S, got.N)
+	// Output: str 10
+}
+
+func ExampleWithEnvPrefix() {
+	type T struct {
+		Port int `name:"port" default:"80" usage:"port number"`
+	}
+
+	sc := structconfig.New[T](
+		structconfig.WithEnvPrefix("myapp_"),
+		structconfig.WithEnviron([]string{"PORT=1", "MYAPP_PORT=8080"}),
+	)
+	var got T
+	if err := sc.FromEnv(&got); err != nil {
+		panic(err)
+	}
+	fmt.Println(got.Port)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := sc.SetFlags(fs); err != nil {
+		panic(err)
+	}
+	fmt.Print(fs.FlagUsages())
+	// Output:
+	// 8080
+	//       --port int   port number (env MYAPP_PORT) (default 80)
+}
+
+func ExampleStructConfig_Dump() {
+	type T struct {
+		Host  string   `name:"host" default:"localhost" usage:"listen host"`
+		Port  int      `name:"port" default:"80" usage:"listen port"`
+		Hosts []string `name:"hosts" usage:"upstreams"`
+	}
+
+	sc := structconfig.New[T](structconfig.WithEnvPrefix("app_"))
+	var provenance structconfig.Provenance
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c, err := structconfig.NewConfigWithMerge[T](
+		sc,
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron([]string{"APP_HOSTS=a,b"}),
+		structconfig.WithArguments([]string{"--port", "8080"}),
+		structconfig.WithProvenance(&provenance),
+	)
+	if err != nil {
+		panic(err)
+	}
+	if err := sc.Dump(os.Stdout, c, provenance, structconfig.DumpTable); err != nil {
+		panic(err)
+	}
+	// Output:
+	// FIELD  FLAG     ENV        VALUE      DEFAULT    SOURCE   USAGE
+	// Host   --host   APP_HOST   localhost  localhost  default  listen host
+	// Port   --port   APP_PORT   8080       80         flag     listen port
+	// Hosts  --hosts  APP_HOSTS  a,b                   env      upstreams
+}
+
+func ExampleStructConfig_Redact() {
+	type T struct {
+		User     string `name:"user" default:"admin"`
+		Password string `name:"password" default:"changeme" secret:"true" usage:"login password"`
+	}
+
+	sc := structconfig.New[T]()
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	if err := sc.SetFlags(fs); err != nil {
+		panic(err)
+	}
+	fmt.Print(fs.FlagUsages())
+
+	c := T{
+		User:     "root",
+		Password: "hunter2",
+	}
+	fmt.Println(sc.Redact(&c))
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{
+		ReplaceAttr: func(_ []string, a slog.Attr) slog.Attr {
+			if a.Key == slog.TimeKey {
+				return slog.Attr{}
+			}
+			return a
+		},
+	}))
+	logger.Info("loaded", "config", sc.Redact(&c))
+	// Output:
+	//       --password string   login password (default "******")
+	//       --user string        (default "admin")
+	// {User:root Password:******}
+	// level=INFO msg=loaded config.User=root config.Password=******
+}
+
+func ExampleWithEnvFile() {
+	type T struct {
+		User     string `name:"db_user"`
+		Password string `name:"db_password" secret:"true"`
+	}
+
+	dir, err := os.MkdirTemp("", "structconfig")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "db_password")
+	if err := os.WriteFile(path, []byte("hunter2\n"), 0o600); err != nil {
+		panic(err)
+	}
+
+	sc := structconfig.New[T](
+		structconfig.WithEnvFile(structconfig.EnvFileSecret),
+		structconfig.WithEnviron([]string{
+			"DB_USER=root",
+			"DB_PASSWORD_FILE=" + path,
+		}),
+	)
+	var got T
+	if err := sc.FromEnv(&got); err != nil {
+		panic(err)
+	}
+	fmt.Println(got.User, got.Password)
+	// Output: root hunter2
+}
@@ -1,8 +1,10 @@
 package structconfig_test
 
 import (
+	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 
 	"github.com/berquerant/structconfig"
 	"github.com/spf13/pflag"
@@ -45,3 +47,305 @@ func ExampleNewConfigWithMerge() {
 	fmt.Println(c.Default, c.Env, c.Flag, c.Override)
 	// Output: default from_env from_flag overrided
 }
+
+func ExampleNewConfigWithMerge_configFile() {
+	type DB struct {
+		Host string `name:"host" default:"localhost"`
+		Port int    `name:"port" default:"5432"`
+	}
+	type T struct {
+		DB    DB       `name:"db"`
+		Hosts []string `name:"hosts"`
+		Debug bool     `name:"debug"`
+	}
+
+	dir, err := os.MkdirTemp("", "structconfig")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"db":{"host":"db.example.com"},"hosts":["a","b"],"debug":true}`), 0o600); err != nil {
+		panic(err)
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithConfigFile(path),
+		structconfig.WithArguments([]string{"--db.port", "15432"}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(c.DB.Host, c.DB.Port, c.Hosts, c.Debug)
+	// Output: db.example.com 15432 [a b] true
+}
+
+func ExampleNewConfigWithMerge_environ() {
+	type T struct {
+		Env  string `name:"env_value" default:"env_default"`
+		Flag string `name:"flag_value" default:"flag_default"`
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron([]string{"ENV_VALUE=from_environ"}),
+		structconfig.WithArguments([]string{"--flag_value", "from_flag"}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(c.Env, c.Flag)
+	// Output: from_environ from_flag
+}
+
+func ExampleNewConfigWithMerge_sourceTags() {
+	type DB struct {
+		Host string `name:"host" flag:"db-host" env:"DATABASE_URL_HOST" key:"database.host" default:"localhost"`
+		Port int    `name:"port" default:"5432"`
+		User string `name:"user" default:"root"`
+	}
+	type T struct {
+		DB DB `name:"db"`
+	}
+
+	dir, err := os.MkdirTemp("", "structconfig")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"database":{"host":"from_file"},"db":{"user":"from_file"}}`), 0o600); err != nil {
+		panic(err)
+	}
+
+	for _, args := range [][]string{
+		{},
+		{"--db-host", "from_flag"},
+	} {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		c, err := structconfig.NewConfigWithMerge[T](
+			structconfig.New[T](),
+			structconfig.NewMerger[T](),
+			fs,
+			structconfig.WithConfigFile(path),
+			structconfig.WithEnviron([]string{"DATABASE_URL_HOST=from_env", "DB_PORT=15432"}),
+			structconfig.WithArguments(args),
+		)
+		if err != nil {
+			panic(err)
+		}
+		fmt.Println(c.DB.Host, c.DB.Port, c.DB.User)
+	}
+	// Output:
+	// from_env 15432 from_file
+	// from_flag 15432 from_file
+}
+
+func ExampleNewConfigWithMerge_required() {
+	type T struct {
+		Token string `name:"api_token" required:"true"`
+		DSN   string `name:"dsn" required:"true"`
+		Port  int    `name:"port" default:"80" required:"true"`
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	_, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron(nil),
+		structconfig.WithArguments([]string{}),
+	)
+	fmt.Println(errors.Is(err, structconfig.ErrRequired))
+	fmt.Println(err)
+	// Output:
+	// true
+	// StructConfig: Required: missing Token (flag --api_token, env API_TOKEN), DSN (flag --dsn, env DSN)
+}
+
+func ExampleNewConfigWithMerge_requiredExplicitZero() {
+	type T struct {
+		Replicas int  `name:"replicas" required:"true"`
+		Debug    bool `name:"debug" required:"true"`
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron([]string{"DEBUG=false"}),
+		structconfig.WithArguments([]string{"--replicas=0"}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(c.Replicas, c.Debug)
+	// Output: 0 false
+}
+
+func ExampleNewConfigWithMerge_secretFlag() {
+	type T struct {
+		PIN int `name:"pin" secret:"true"`
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	_, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron(nil),
+		structconfig.WithArguments([]string{"--pin=12x4"}),
+	)
+	fmt.Println(err)
+	// Output: invalid argument "******" for "--pin" flag: strconv.ParseInt: parsing "******": invalid syntax
+}
+
+func ExampleNewConfigWithMerge_validation() {
+	type T struct {
+		Port  int    `name:"port" default:"80" min:"1" max:"65535"`
+		Level string `name:"level" default:"info" oneof:"debug|info|warn"`
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	_, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron([]string{"LEVEL=trace"}),
+		structconfig.WithArguments([]string{"--port", "70000"}),
+	)
+	var verr *structconfig.ValidationError
+	fmt.Println(errors.As(err, &verr), len(verr.Violations))
+	fmt.Println(err)
+	// Output:
+	// true 2
+	// StructConfig: Validation: Port (flag --port, env PORT) from flag --port: 70000 is greater than max 65535; Level (flag --level, env LEVEL) from env LEVEL: trace is not one of debug|info|warn
+}
+
+func ExampleNewConfigWithMerge_provenance() {
+	type T struct {
+		Host string `name:"host" default:"localhost"`
+		Port int    `name:"port" default:"80"`
+		Env  string `name:"env" default:"dev"`
+	}
+
+	var provenance structconfig.Provenance
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	_, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron([]string{"APP_HOST=example.com"}),
+		structconfig.WithEnvPrefix("app_"),
+		structconfig.WithArguments([]string{"--port", "8080"}),
+		structconfig.WithProvenance(&provenance),
+	)
+	if err != nil {
+		panic(err)
+	}
+	for _, name := range []string{"Host", "Port", "Env"} {
+		fmt.Println(name, provenance[name])
+	}
+	// Output:
+	// Host env APP_HOST="example.com"
+	// Port flag "8080"
+	// Env default "dev"
+}
+
+func ExampleWithExpand() {
+	type T struct {
+		CacheDir string `name:"cache_dir" default:"${HOME}/.cache/app"`
+		Socket   string `name:"socket" default:"${XDG_RUNTIME_DIR:-/tmp}/app.sock"`
+		LogDir   string `name:"log_dir" default:"${HOME}/log"`
+	}
+
+	dir, err := os.MkdirTemp("", "structconfig")
+	if err != nil {
+		panic(err)
+	}
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "config.json")
+	if err := os.WriteFile(path, []byte(`{"log_dir":"/var/log/app"}`), 0o600); err != nil {
+		panic(err)
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithExpand(true),
+		structconfig.WithEnviron([]string{"HOME=/home/user"}),
+		structconfig.WithConfigFile(path),
+		structconfig.WithArguments([]string{}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(c.CacheDir)
+	fmt.Println(c.Socket)
+	fmt.Println(c.LogDir)
+	// Output:
+	// /home/user/.cache/app
+	// /tmp/app.sock
+	// /var/log/app
+}
+
+func ExampleNewConfigWithMerge_defaultRefs() {
+	type T struct {
+		ListenHost  string `name:"listen_host" default:"0.0.0.0"`
+		MetricsAddr string `name:"metrics_addr" default:"${.listen_host}:9090"`
+		DataDir     string `name:"data_dir" default:"/var/lib/app"`
+		CacheDir    string `name:"cache_dir" default:"${.data_dir}/cache"`
+		BackupDir   string `name:"backup_dir" default:"${.data_dir}/backup"`
+	}
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron([]string{"DATA_DIR=/data"}),
+		structconfig.WithArguments([]string{"--listen_host", "127.0.0.1", "--backup_dir="}),
+	)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(c.MetricsAddr)
+	fmt.Println(c.CacheDir)
+	fmt.Printf("%q\n", c.BackupDir)
+	// Output:
+	// 127.0.0.1:9090
+	// /data/cache
+	// ""
+}
+
+func ExampleNewConfigWithMerge_explicitDefault() {
+	type T struct {
+		Replicas int `name:"replicas" default:"3"`
+	}
+
+	var provenance structconfig.Provenance
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	c, err := structconfig.NewConfigWithMerge[T](
+		structconfig.New[T](),
+		structconfig.NewMerger[T](),
+		fs,
+		structconfig.WithEnviron([]string{"REPLICAS=5"}),
+		structconfig.WithArguments([]string{"--replicas=3"}),
+		structconfig.WithProvenance(&provenance),
+	)
+	if err != nil {
+		panic(err)
+	}
+	fmt.Println(c.Replicas, provenance["Replicas"])
+	// Output: 3 flag "3"
+}
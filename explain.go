@@ -0,0 +1,59 @@
+package structconfig
+
+import (
+	"io"
+
+	"github.com/berquerant/structconfig/internal"
+)
+
+type Explanation = internal.Explanation
+
+// DumpFormat is the output format of [StructConfig.Dump].
+type DumpFormat int
+
+const (
+	// DumpTable is a table aligned by spaces with a header.
+	DumpTable DumpFormat = iota
+	// DumpJSON is a JSON array of [Explanation].
+	DumpJSON
+)
+
+// Explain returns the effective configuration of the fields of v that have "name" tag:
+// the Go field name, the flag name, the environment variable name, the value, "default" tag value,
+// the source and "usage" tag value.
+//
+// The value is formatted like "default" tag value, e.g. the elements of a slice are joined by "sep" tag value.
+// The source is from provenance, see [Builder.BuildWithProvenance]; it is empty if provenance is nil.
+func (sc StructConfig[T]) Explain(v *T, provenance Provenance) ([]*Explanation, error) {
+	typ, err := sc.newType()
+	if err != nil {
+		return nil, err
+	}
+	xs, err := internal.Explain(v, typ, sc.envPrefix)
+	if err != nil {
+		return nil, err
+	}
+	for _, x := range xs {
+		if o, ok := provenance[x.Field]; ok {
+			x.Source = o.Source
+		}
+	}
+	return xs, nil
+}
+
+// Dump writes [StructConfig.Explain] of v to w in format,
+// e.g. for a --print-config flag.
+func (sc StructConfig[T]) Dump(w io.Writer, v *T, provenance Provenance, format DumpFormat) error {
+	xs, err := sc.Explain(v, provenance)
+	if err != nil {
+		return err
+	}
+	switch format {
+	case DumpTable:
+		return internal.WriteExplanationTable(w, xs)
+	case DumpJSON:
+		return internal.WriteExplanationJSON(w, xs)
+	default:
+		return internal.Errorf("unknown dump format %d", format)
+	}
+}
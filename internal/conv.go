@@ -1,6 +1,9 @@
 package internal
 
-import "strconv"
+import (
+	"reflect"
+	"strconv"
+)
 
 // Converter is a set of string conversions.
 type Converter interface {
@@ -85,3 +88,53 @@ func NewConv() *DefaultConverter {
 		StringFunc:  func(s string) (string, error) { return s, nil },
 	}
 }
+
+// ConvertValue converts s by conv according to the kind of v and sets it to v.
+// The kind of v should be supported.
+// [time.Duration] is parsed by [time.ParseDuration] instead of the kind.
+func ConvertValue(conv Converter, v reflect.Value, s string) error {
+	if v.Type() == durationType {
+		return SetTime(v, s, "")
+	}
+	switch v.Kind() {
+	case reflect.Bool:
+		return convertTo(conv.Bool, s, v.SetBool)
+	case reflect.Int:
+		return convertTo(conv.Int, s, func(x int) { v.SetInt(int64(x)) })
+	case reflect.Int8:
+		return convertTo(conv.Int8, s, func(x int8) { v.SetInt(int64(x)) })
+	case reflect.Int16:
+		return convertTo(conv.Int16, s, func(x int16) { v.SetInt(int64(x)) })
+	case reflect.Int32:
+		return convertTo(conv.Int32, s, func(x int32) { v.SetInt(int64(x)) })
+	case reflect.Int64:
+		return convertTo(conv.Int64, s, v.SetInt)
+	case reflect.Uint:
+		return convertTo(conv.Uint, s, func(x uint) { v.SetUint(uint64(x)) })
+	case reflect.Uint8:
+		return convertTo(conv.Uint8, s, func(x uint8) { v.SetUint(uint64(x)) })
+	case reflect.Uint16:
+		return convertTo(conv.Uint16, s, func(x uint16) { v.SetUint(uint64(x)) })
+	case reflect.Uint32:
+		return convertTo(conv.Uint32, s, func(x uint32) { v.SetUint(uint64(x)) })
+	case reflect.Uint64:
+		return convertTo(conv.Uint64, s, v.SetUint)
+	case reflect.Float32:
+		return convertTo(conv.Float32, s, func(x float32) { v.SetFloat(float64(x)) })
+	case reflect.Float64:
+		return convertTo(conv.Float64, s, v.SetFloat)
+	case reflect.String:
+		return convertTo(conv.String, s, v.SetString)
+	default:
+		return Errorf("cannot set %s to %s", s, v.Type())
+	}
+}
+
+func convertTo[T any](conv func(string) (T, error), s string, set func(T)) error {
+	x, err := conv(s)
+	if err != nil {
+		return err
+	}
+	set(x)
+	return nil
+}
@@ -3,14 +3,20 @@ package internal
 import "reflect"
 
 // DefaultReceptor sets default tag value to the struct field.
+// The default tag value is expanded by expand if expand is not nil, see [Expander].
 //
 // ptr should be a pointer of struct.
 func DefaultReceptor(
 	ptr any,
 	anyCallback func(StructField, string, func() reflect.Value) error,
+	registry *Registry,
+	expand func(string) (string, error),
 ) (*PairsReceptor, error) {
 	get := func(s StructField) (string, error) {
-		if v, ok := s.Tag().Default(); ok {
+		switch v, ok, err := defaultValue(s, expand); {
+		case err != nil:
+			return "", err
+		case ok:
 			return v, nil
 		}
 		return "", ErrSkipParse
@@ -21,5 +27,6 @@ func DefaultReceptor(
 		get,
 		NewConv(),
 		anyCallback,
+		registry,
 	)
 }
@@ -17,7 +17,10 @@ func TestDefaultReceptor(t *testing.T) {
 		F         float32 `default:"1.1"`
 		S         string  `default:"str"`
 		NoDefault int
-		Slice     []int `default:"[1,2]"`
+		Slice     []int          `default:"1,2"`
+		Sep       []string       `default:"a;b" sep:";"`
+		Map       map[string]int `default:"a=1,b=2"`
+		Any       [][]int        `default:"[[1],[2]]"`
 	}
 
 	want := T{
@@ -27,6 +30,9 @@ func TestDefaultReceptor(t *testing.T) {
 		F:     1.1,
 		S:     "str",
 		Slice: []int{1, 2},
+		Sep:   []string{"a", "b"},
+		Map:   map[string]int{"a": 1, "b": 2},
+		Any:   [][]int{{1}, {2}},
 	}
 
 	var got T
@@ -34,13 +40,15 @@ func TestDefaultReceptor(t *testing.T) {
 	r, err := internal.DefaultReceptor(
 		&got,
 		func(_ internal.StructField, v string, fv func() reflect.Value) error {
-			var xs []int
+			var xs [][]int
 			if err := json.Unmarshal([]byte(v), &xs); err != nil {
 				return err
 			}
 			fv().Set(reflect.ValueOf(xs))
 			return nil
 		},
+		nil,
+		nil,
 	)
 	assert.Nil(t, err)
 
@@ -50,3 +58,27 @@ func TestDefaultReceptor(t *testing.T) {
 	assert.Nil(t, typ.Accept(r))
 	assert.Equal(t, want, got)
 }
+
+func TestDefaultReceptorPointer(t *testing.T) {
+	type T struct {
+		I         *int    `default:"1"`
+		S         *string `default:""`
+		NoDefault *int
+	}
+
+	var got T
+	r, err := internal.DefaultReceptor(&got, nil, nil, nil)
+	assert.Nil(t, err)
+
+	typ, err := internal.NewType(got, "")
+	assert.Nil(t, err)
+
+	assert.Nil(t, typ.Accept(r))
+	if assert.NotNil(t, got.I) {
+		assert.Equal(t, 1, *got.I)
+	}
+	if assert.NotNil(t, got.S) {
+		assert.Equal(t, "", *got.S)
+	}
+	assert.Nil(t, got.NoDefault)
+}
@@ -0,0 +1,280 @@
+package internal
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// Document is a tree of values decoded from a configuration file.
+//
+// The values are nil, string, bool, numbers, []any or Document (or map[string]any).
+// They can be wrapped by [*DocumentNode] to hold the position in the file.
+type Document map[string]any
+
+// DocumentNode is a value of [Document] with the position in the file.
+type DocumentNode struct {
+	Value  any
+	Line   int
+	Column int
+}
+
+func (n DocumentNode) String() string {
+	return fmt.Sprintf("line %d, column %d", n.Line, n.Column)
+}
+
+func (n DocumentNode) MarshalJSON() ([]byte, error) {
+	return json.Marshal(n.Value)
+}
+
+func unwrapDocumentNode(v any) any {
+	if n, ok := v.(*DocumentNode); ok {
+		return n.Value
+	}
+	return v
+}
+
+// Lookup finds the value by name.
+//
+// name is like "db.host", the key "db.host" and the path "db" -> "host" are searched in order.
+func (d Document) Lookup(name string) (any, bool) {
+	if v, ok := d[name]; ok {
+		return v, true
+	}
+	for i := len(name) - 1; i >= 0; i-- {
+		if !strings.HasPrefix(name[i:], NameSeparator) {
+			continue
+		}
+		child, ok := asDocument(d[name[:i]])
+		if !ok {
+			continue
+		}
+		if v, ok := child.Lookup(name[i+len(NameSeparator):]); ok {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func asDocument(v any) (Document, bool) {
+	switch v := unwrapDocumentNode(v).(type) {
+	case Document:
+		return v, true
+	case map[string]any:
+		return Document(v), true
+	default:
+		return nil, false
+	}
+}
+
+// FormatDocumentValue converts v, the value of [Document], into a string for s.
+//
+// Arrays are joined by the sep tag value, objects are formatted like "k1=v1,k2=v2"; see [SplitSlice], [SplitMap].
+// The joined string is for display because the elements can contain the separators;
+// [DocumentReceptor] sets the arrays and the objects element by element.
+// Arrays and objects for the other types are formatted as JSON for anyCallback.
+func FormatDocumentValue(s StructField, v any) (string, error) {
+	v = unwrapDocumentNode(v)
+	switch v.(type) {
+	case []any, Document, map[string]any:
+		if !IsSupportedSlice(s.FieldType()) && !IsSupportedMap(s.FieldType()) {
+			b, err := json.Marshal(v)
+			if err != nil {
+				return "", JoinErrors(Errorf("%s: format json", s.Name()), err)
+			}
+			return string(b), nil
+		}
+	}
+
+	if v, ok := v.([]any); ok {
+		xs := make([]string, len(v))
+		for i, x := range v {
+			y, err := FormatDocumentValue(s, x)
+			if err != nil {
+				return "", err
+			}
+			xs[i] = y
+		}
+		return strings.Join(xs, s.Tag().Sep()), nil
+	}
+
+	if d, ok := asDocument(v); ok {
+		keys := make([]string, 0, len(d))
+		for k := range d {
+			keys = append(keys, k)
+		}
+		slices.Sort(keys)
+		xs := make([]string, len(keys))
+		for i, k := range keys {
+			y, err := FormatDocumentValue(s, d[k])
+			if err != nil {
+				return "", err
+			}
+			xs[i] = k + MapKeyValueSeparator + y
+		}
+		return strings.Join(xs, s.Tag().Sep()), nil
+	}
+	return formatDocumentScalar(s, v)
+}
+
+// formatDocumentScalar converts v, the value of [Document] other than arrays and objects, into a string.
+func formatDocumentScalar(s StructField, v any) (string, error) {
+	switch v := unwrapDocumentNode(v).(type) {
+	case string:
+		return v, nil
+	case bool:
+		return strconv.FormatBool(v), nil
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), nil
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32), nil
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64, json.Number:
+		return fmt.Sprint(v), nil
+	default:
+		return "", Errorf("%s: cannot accept %s", s.Name(), reflect.TypeOf(v))
+	}
+}
+
+// lookupDocumentElements finds the array of s that is a slice of a supported kind,
+// or the object of s that is a map from string to a supported kind.
+func lookupDocumentElements(doc Document, s StructField) (any, bool) {
+	key, ok := s.Tag().Key()
+	if !ok {
+		return nil, false
+	}
+	v, ok := doc.Lookup(key)
+	if !ok {
+		return nil, false
+	}
+	switch v := unwrapDocumentNode(v).(type) {
+	case []any:
+		return v, IsSupportedSlice(s.FieldType())
+	default:
+		d, ok := asDocument(v)
+		return d, ok && IsSupportedMap(s.FieldType())
+	}
+}
+
+// setDocumentElements converts the elements of v, the array or the object of [Document], and sets them to the field.
+func setDocumentElements(s StructField, field reflect.Value, v any) error {
+	conv := NewConv()
+	set := func(dst reflect.Value, x any) error {
+		raw, err := formatDocumentScalar(s, x)
+		if err != nil {
+			return err
+		}
+		return RedactError(s, raw, ConvertValue(conv, dst, raw))
+	}
+
+	typ := field.Type()
+	if xs, ok := v.([]any); ok {
+		r := reflect.MakeSlice(typ, len(xs), len(xs))
+		for i, x := range xs {
+			if err := set(r.Index(i), x); err != nil {
+				return err
+			}
+		}
+		field.Set(r)
+		return nil
+	}
+
+	d := v.(Document)
+	r := reflect.MakeMapWithSize(typ, len(d))
+	for k, x := range d {
+		key := reflect.New(typ.Key()).Elem()
+		key.SetString(k)
+		value := reflect.New(typ.Elem()).Elem()
+		if err := set(value, x); err != nil {
+			return err
+		}
+		r.SetMapIndex(key, value)
+	}
+	field.Set(r)
+	return nil
+}
+
+var _ Receptor = documentElementsReceptor{}
+
+// documentElementsReceptor sets the arrays and the objects of [Document] to the slices and the maps
+// element by element instead of [FormatDocumentValue] so that the elements can contain the separators.
+type documentElementsReceptor struct {
+	*PairsReceptor
+	ptr      reflect.Value
+	doc      Document
+	registry *Registry
+}
+
+func (r documentElementsReceptor) Any(s StructField) error {
+	if _, ok := r.registry.Lookup(s.FieldType()); !ok {
+		if v, ok := lookupDocumentElements(r.doc, s); ok {
+			return setDocumentElements(s, r.ptr.Elem().FieldByIndex(s.Index()), v)
+		}
+	}
+	return r.PairsReceptor.Any(s)
+}
+
+// DocumentReceptor sets the values of [Document] to the struct field.
+// The values are found by [Tag.Key], see [Document.Lookup].
+// The arrays and the objects are set to the slices and the maps of supported kinds element by element.
+// If not found, default tag value is used, expanded by expand if expand is not nil, see [Expander].
+//
+// The errors include the Go field name, the name tag value
+// and the position of the value if it is [*DocumentNode].
+//
+// ptr should be a pointer of struct.
+func DocumentReceptor(
+	ptr any,
+	doc Document,
+	anyCallback func(StructField, string, func() reflect.Value) error,
+	registry *Registry,
+	expand func(string) (string, error),
+) (*ErrorWrapReceptor, error) {
+	get := func(s StructField) (string, error) {
+		if _, ok := s.Tag().Name(); !ok {
+			// ignore the field
+			return "", ErrSkipParse
+		}
+		if key, ok := s.Tag().Key(); ok {
+			if v, ok := doc.Lookup(key); ok && unwrapDocumentNode(v) != nil {
+				return FormatDocumentValue(s, v)
+			}
+		}
+		switch v, ok, err := defaultValue(s, expand); {
+		case err != nil:
+			return "", err
+		case ok:
+			return v, nil
+		}
+		return "", ErrSkipParse
+	}
+
+	r, err := SetReceptor(
+		ptr,
+		get,
+		NewConv(),
+		anyCallback,
+		registry,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return NewErrorWrapReceptor(documentElementsReceptor{
+		PairsReceptor: r,
+		ptr:           reflect.ValueOf(ptr),
+		doc:           doc,
+		registry:      registry,
+	}, func(s StructField, err error) error {
+		name, _ := s.Tag().Name()
+		if key, ok := s.Tag().Key(); ok {
+			if v, ok := doc.Lookup(key); ok {
+				if n, ok := v.(*DocumentNode); ok {
+					return fmt.Errorf("%w: %s: %s (%s): %w", ErrStructConfig, n, s.Name(), name, err)
+				}
+			}
+		}
+		return fmt.Errorf("%w: %s (%s): %w", ErrStructConfig, s.Name(), name, err)
+	}), nil
+}
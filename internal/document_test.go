@@ -0,0 +1,177 @@
+package internal_test
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDocumentLookup(t *testing.T) {
+	doc := internal.Document{
+		"a": "A",
+		"b": map[string]any{
+			"c": "BC",
+			"d": map[string]any{
+				"e": "BDE",
+			},
+		},
+		"f.g": "FG",
+		"h": map[string]any{
+			"i.j": "HIJ",
+		},
+	}
+
+	for _, tc := range []struct {
+		name string
+		want any
+		ok   bool
+	}{
+		{name: "a", want: "A", ok: true},
+		{name: "b.c", want: "BC", ok: true},
+		{name: "b.d.e", want: "BDE", ok: true},
+		{name: "f.g", want: "FG", ok: true},
+		{name: "h.i.j", want: "HIJ", ok: true},
+		{name: "b.x"},
+		{name: "a.b"},
+		{name: "x"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := doc.Lookup(tc.name)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDocumentReceptor(t *testing.T) {
+	type DB struct {
+		Host string `name:"host"`
+		Port int    `name:"port" default:"5432"`
+	}
+	type T struct {
+		B         bool              `name:"b"`
+		I         int               `name:"i"`
+		F         float64           `name:"f"`
+		S         string            `name:"s" default:"str"`
+		NoDefault int               `name:"no_default"`
+		Null      int               `name:"null" default:"10"`
+		Slice     []int             `name:"slice"`
+		Map       map[string]int    `name:"map"`
+		D         time.Duration     `name:"d"`
+		DB        DB                `name:"db"`
+		Any       [][]int           `name:"any"`
+		Ignore    []int             `name:"-"`
+		Labels    map[string]string `name:"labels"`
+		Tags      []string          `name:"tags"`
+		Env       map[string]string `name:"env"`
+	}
+
+	const input = `{
+  "b": true,
+  "i": 1,
+  "f": 1.5,
+  "null": null,
+  "slice": [1, 2],
+  "map": {"a": 1, "b": 2},
+  "d": "1m",
+  "db": {"host": "localhost"},
+  "any": [[1], [2]],
+  "Ignore": [1],
+  "labels.x": "not a label",
+  "tags": ["a,b", "c"],
+  "env": {"PATH": "/bin,/usr/bin", "OPT": "a=b"}
+}`
+
+	want := T{
+		B:     true,
+		I:     1,
+		F:     1.5,
+		S:     "str",
+		Null:  10,
+		Slice: []int{1, 2},
+		Map:   map[string]int{"a": 1, "b": 2},
+		D:     time.Minute,
+		DB: DB{
+			Host: "localhost",
+			Port: 5432,
+		},
+		Any:  [][]int{{1}, {2}},
+		Tags: []string{"a,b", "c"},
+		Env:  map[string]string{"PATH": "/bin,/usr/bin", "OPT": "a=b"},
+	}
+
+	doc, err := internal.DecodeJSON(strings.NewReader(input))
+	assert.Nil(t, err)
+
+	var got T
+	r, err := internal.DocumentReceptor(
+		&got,
+		doc,
+		func(_ internal.StructField, v string, fv func() reflect.Value) error {
+			var xs [][]int
+			if err := json.Unmarshal([]byte(v), &xs); err != nil {
+				return err
+			}
+			fv().Set(reflect.ValueOf(xs))
+			return nil
+		},
+		nil,
+		nil,
+	)
+	assert.Nil(t, err)
+
+	typ, err := internal.NewType(got, "")
+	assert.Nil(t, err)
+
+	assert.Nil(t, typ.Accept(r))
+	assert.Equal(t, want, got)
+}
+
+func TestDocumentReceptorElementError(t *testing.T) {
+	type T struct {
+		Slice []int          `name:"slice"`
+		Map   map[string]int `name:"map"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	for _, tc := range []struct {
+		title string
+		input string
+	}{
+		{title: "nested array", input: `{"slice": [[1]]}`},
+		{title: "invalid element", input: `{"slice": ["x"]}`},
+		{title: "null element", input: `{"slice": [null]}`},
+		{title: "invalid value", input: `{"map": {"a": "x"}}`},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			doc, err := internal.DecodeJSON(strings.NewReader(tc.input))
+			assert.Nil(t, err)
+			var got T
+			r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+			assert.Nil(t, err)
+			assert.ErrorIs(t, typ.Accept(r), internal.ErrStructConfig)
+		})
+	}
+}
+
+func TestDecodeJSON(t *testing.T) {
+	t.Run("invalid", func(t *testing.T) {
+		_, err := internal.DecodeJSON(strings.NewReader(`{"a":`))
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+	})
+	t.Run("not object", func(t *testing.T) {
+		_, err := internal.DecodeJSON(strings.NewReader(`[1]`))
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+	})
+	t.Run("number", func(t *testing.T) {
+		doc, err := internal.DecodeJSON(strings.NewReader(`{"a":9007199254740993}`))
+		assert.Nil(t, err)
+		assert.Equal(t, json.Number("9007199254740993"), doc["a"])
+	})
+}
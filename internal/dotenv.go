@@ -0,0 +1,289 @@
+package internal
+
+import (
+	"io"
+	"strings"
+)
+
+// Dotenv is a set of environment variables read from a dotenv file.
+type Dotenv map[string]string
+
+// Lookup retrieves the value of the environment variable.
+// This can be passed to [EnvReceptor] instead of [os.LookupEnv].
+func (d Dotenv) Lookup(name string) (string, bool) {
+	v, ok := d[name]
+	return v, ok
+}
+
+// DecodeDotenv reads a dotenv file.
+//
+// Each line is like
+//
+//	# comment
+//	KEY=value # comment
+//	export KEY='literal value'
+//	KEY="value with\nescapes and ${OTHER}"
+//
+// Unquoted and double-quoted values expand ${VAR} and $VAR
+// by the variables defined earlier in the file, or by lookup if not defined.
+// lookup can be nil.
+// Double-quoted and single-quoted values can span multiple lines.
+func DecodeDotenv(r io.Reader, lookup func(string) (string, bool)) (Dotenv, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, JoinErrors(Errorf("decode dotenv"), err)
+	}
+	p := &dotenvParser{
+		src:    strings.ReplaceAll(string(b), "\r\n", "\n"),
+		line:   1,
+		lookup: lookup,
+		env:    Dotenv{},
+	}
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.env, nil
+}
+
+type dotenvParser struct {
+	src    string
+	pos    int
+	line   int
+	lookup func(string) (string, bool)
+	env    Dotenv
+}
+
+func (p *dotenvParser) errorf(format string, v ...any) error {
+	return Errorf("decode dotenv: line %d: "+format, append([]any{p.line}, v...)...)
+}
+
+func (p *dotenvParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *dotenvParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *dotenvParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	if c == '\n' {
+		p.line++
+	}
+	return c
+}
+
+func (p *dotenvParser) skipSpace() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.next()
+	}
+}
+
+func (p *dotenvParser) skipLine() {
+	for !p.eof() && p.next() != '\n' {
+	}
+}
+
+func (p *dotenvParser) parse() error {
+	for {
+		p.skipSpace()
+		switch c := p.peek(); {
+		case p.eof():
+			return nil
+		case c == '\n':
+			p.next()
+			continue
+		case c == '#':
+			p.skipLine()
+			continue
+		}
+		if err := p.parseAssignment(); err != nil {
+			return err
+		}
+	}
+}
+
+func isDotenvKeyChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '.' || c == '-'
+}
+
+func (p *dotenvParser) parseKey() string {
+	start := p.pos
+	for isDotenvKeyChar(p.peek()) {
+		p.next()
+	}
+	return p.src[start:p.pos]
+}
+
+func (p *dotenvParser) parseAssignment() error {
+	key := p.parseKey()
+	if key == "export" && (p.peek() == ' ' || p.peek() == '\t') {
+		p.skipSpace()
+		key = p.parseKey()
+	}
+	if key == "" {
+		return p.errorf("invalid key")
+	}
+	p.skipSpace()
+	if p.peek() != '=' {
+		return p.errorf("%s: expected =", key)
+	}
+	p.next()
+	p.skipSpace()
+
+	var (
+		value string
+		err   error
+	)
+	switch p.peek() {
+	case '\'':
+		value, err = p.parseSingleQuoted()
+	case '"':
+		value, err = p.parseDoubleQuoted()
+	default:
+		value, err = p.parseUnquoted()
+	}
+	if err != nil {
+		return err
+	}
+	p.env[key] = value
+	return p.expectEOL()
+}
+
+func (p *dotenvParser) expectEOL() error {
+	p.skipSpace()
+	switch c := p.peek(); {
+	case p.eof():
+		return nil
+	case c == '\n':
+		p.next()
+		return nil
+	case c == '#':
+		p.skipLine()
+		return nil
+	default:
+		return p.errorf("unexpected %q", c)
+	}
+}
+
+func (p *dotenvParser) parseSingleQuoted() (string, error) {
+	line := p.line
+	p.next() // '
+	start := p.pos
+	for !p.eof() {
+		if p.peek() == '\'' {
+			v := p.src[start:p.pos]
+			p.next()
+			return v, nil
+		}
+		p.next()
+	}
+	p.line = line
+	return "", p.errorf("unterminated quoted value")
+}
+
+func (p *dotenvParser) parseDoubleQuoted() (string, error) {
+	line := p.line
+	p.next() // "
+	var b strings.Builder
+	for !p.eof() {
+		switch c := p.peek(); c {
+		case '"':
+			p.next()
+			return b.String(), nil
+		case '\\':
+			p.next()
+			if p.eof() {
+				break
+			}
+			switch e := p.next(); e {
+			case 'n':
+				b.WriteByte('\n')
+			case 'r':
+				b.WriteByte('\r')
+			case 't':
+				b.WriteByte('\t')
+			case '"', '\\', '$':
+				b.WriteByte(e)
+			default:
+				b.WriteByte('\\')
+				b.WriteByte(e)
+			}
+		case '$':
+			if err := p.expand(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(p.next())
+		}
+	}
+	p.line = line
+	return "", p.errorf("unterminated quoted value")
+}
+
+// parseUnquoted reads the value until the end of the line or the comment preceded by a space.
+func (p *dotenvParser) parseUnquoted() (string, error) {
+	var b strings.Builder
+	for !p.eof() {
+		c := p.peek()
+		if c == '\n' {
+			break
+		}
+		if c == '#' && p.pos > 0 && (p.src[p.pos-1] == ' ' || p.src[p.pos-1] == '\t') {
+			break
+		}
+		if c == '$' {
+			if err := p.expand(&b); err != nil {
+				return "", err
+			}
+			continue
+		}
+		b.WriteByte(p.next())
+	}
+	return strings.TrimRight(b.String(), " \t"), nil
+}
+
+func isDotenvVarChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_'
+}
+
+// expand reads ${VAR} or $VAR and writes the value of it.
+// An undefined variable is expanded to an empty string.
+func (p *dotenvParser) expand(b *strings.Builder) error {
+	p.next() // $
+	var name string
+	switch {
+	case p.peek() == '{':
+		p.next()
+		start := p.pos
+		for !p.eof() && p.peek() != '}' && p.peek() != '\n' {
+			p.next()
+		}
+		if p.peek() != '}' {
+			return p.errorf("unterminated ${")
+		}
+		name = p.src[start:p.pos]
+		p.next()
+	case isDotenvVarChar(p.peek()):
+		start := p.pos
+		for isDotenvVarChar(p.peek()) {
+			p.next()
+		}
+		name = p.src[start:p.pos]
+	default:
+		b.WriteByte('$')
+		return nil
+	}
+	if v, ok := p.env[name]; ok {
+		b.WriteString(v)
+		return nil
+	}
+	if p.lookup != nil {
+		if v, ok := p.lookup(name); ok {
+			b.WriteString(v)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,169 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeDotenv(t *testing.T) {
+	lookup := func(name string) (string, bool) {
+		if name == "OUTER" {
+			return "outer", true
+		}
+		return "", false
+	}
+
+	for _, tc := range []struct {
+		title string
+		input string
+		want  internal.Dotenv
+	}{
+		{
+			title: "empty",
+			input: "",
+			want:  internal.Dotenv{},
+		},
+		{
+			title: "unquoted",
+			input: `# comment
+A=1
+B = two words # comment
+C=a#b
+export D=d
+E=
+`,
+			want: internal.Dotenv{
+				"A": "1",
+				"B": "two words",
+				"C": "a#b",
+				"D": "d",
+				"E": "",
+			},
+		},
+		{
+			title: "single quoted",
+			input: `A='${B} \n # not comment'
+B='multi
+line'
+`,
+			want: internal.Dotenv{
+				"A": `${B} \n # not comment`,
+				"B": "multi\nline",
+			},
+		},
+		{
+			title: "double quoted",
+			input: `A="a\tb\n\"c\" \$X \w"
+B="multi
+line" # comment
+`,
+			want: internal.Dotenv{
+				"A": "a\tb\n\"c\" $X \\w",
+				"B": "multi\nline",
+			},
+		},
+		{
+			title: "interpolation",
+			input: `HOST=localhost
+URL=http://${HOST}:$PORT/
+PORT=8080
+URL2="${HOST}:${PORT} $OUTER ${UNDEFINED}."
+COST=$ 1
+`,
+			want: internal.Dotenv{
+				"HOST": "localhost",
+				"URL":  "http://localhost:/",
+				"PORT": "8080",
+				"URL2": "localhost:8080 outer .",
+				"COST": "$ 1",
+			},
+		},
+		{
+			title: "crlf",
+			input: "A=1\r\nB='2'\r\n",
+			want: internal.Dotenv{
+				"A": "1",
+				"B": "2",
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := internal.DecodeDotenv(strings.NewReader(tc.input), lookup)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestDecodeDotenvError(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		input string
+		want  string
+	}{
+		{
+			title: "no equal",
+			input: "A=1\nB\n",
+			want:  "line 2: B: expected =",
+		},
+		{
+			title: "invalid key",
+			input: "=1\n",
+			want:  "line 1: invalid key",
+		},
+		{
+			title: "unterminated",
+			input: "A=1\nB=\"x\n",
+			want:  "line 2: unterminated quoted value",
+		},
+		{
+			title: "unterminated interpolation",
+			input: "A=${B\n",
+			want:  "line 1: unterminated ${",
+		},
+		{
+			title: "after quoted value",
+			input: "A='x' y\n",
+			want:  "line 1: unexpected 'y'",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := internal.DecodeDotenv(strings.NewReader(tc.input), nil)
+			assert.ErrorIs(t, err, internal.ErrStructConfig)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func TestDotenvEnvReceptor(t *testing.T) {
+	type T struct {
+		S     string `name:"s"`
+		I     int    `name:"db.port" default:"5432"`
+		Slice []int  `name:"int-slice"`
+		Unset string `name:"unset"`
+	}
+
+	env, err := internal.DecodeDotenv(strings.NewReader(`S="str"
+INT_SLICE=1,2
+`), nil)
+	assert.Nil(t, err)
+
+	t.Setenv("UNSET", "from process")
+
+	var got T
+	r, err := internal.EnvReceptor(&got, "", internal.EnvFileNone, env.Lookup, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	typ, err := internal.NewType(got, "")
+	assert.Nil(t, err)
+
+	assert.Nil(t, typ.Accept(r))
+	assert.Equal(t, T{
+		S:     "str",
+		I:     5432,
+		Slice: []int{1, 2},
+	}, got)
+}
@@ -23,7 +23,48 @@ func (v EnvVar) Get() (string, bool) {
 	return os.LookupEnv(string(v))
 }
 
+// Lookup retrieves the value of the environment variable by lookup.
+// If lookup is nil, same as [EnvVar.Get].
+func (v EnvVar) Lookup(lookup func(string) (string, bool)) (string, bool) {
+	if lookup == nil {
+		return v.Get()
+	}
+	return lookup(string(v))
+}
+
 // String returns the name of the environment variable.
 func (v EnvVar) String() string {
 	return string(v)
 }
+
+// EnvironLookup returns a function like [os.LookupEnv] that retrieves the value from environ.
+// environ is a list of "key=value" like [os.Environ], a later key overrides the same key.
+func EnvironLookup(environ []string) func(string) (string, bool) {
+	env := make(map[string]string, len(environ))
+	for _, x := range environ {
+		k, v, _ := strings.Cut(x, "=")
+		env[k] = v
+	}
+	return func(name string) (string, bool) {
+		v, ok := env[name]
+		return v, ok
+	}
+}
+
+// FieldEnvVar returns the environment variable of s.
+// It is the env tag value if exists, otherwise [NewEnvVar] of envPrefix + name tag value.
+// ok is false if s has no name tag or the env tag value is "-".
+func FieldEnvVar(s StructField, envPrefix string) (EnvVar, bool) {
+	name, ok := s.Tag().Name()
+	if !ok {
+		return "", false
+	}
+	switch v, ok := s.Tag().Env(); {
+	case !ok:
+		return NewEnvVar(envPrefix + name), true
+	case v == TagNameIgnored:
+		return "", false
+	default:
+		return EnvVar(v), true
+	}
+}
@@ -42,3 +42,60 @@ func TestEnvVar(t *testing.T) {
 		})
 	}
 }
+
+func TestEnvironLookup(t *testing.T) {
+	lookup := internal.EnvironLookup([]string{
+		"A=1",
+		"B=x=y",
+		"C=",
+		"A=2",
+	})
+	for _, tc := range []struct {
+		name string
+		want string
+		ok   bool
+	}{
+		{name: "A", want: "2", ok: true},
+		{name: "B", want: "x=y", ok: true},
+		{name: "C", want: "", ok: true},
+		{name: "D"},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := lookup(tc.name)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestFieldEnvVar(t *testing.T) {
+	type DB struct {
+		Host string `name:"host"`
+		URL  string `name:"url" env:"DATABASE_URL"`
+		Skip string `name:"skip" env:"-"`
+	}
+	type T struct {
+		DB     DB  `name:"db"`
+		NoName int `env:"NO_NAME"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	for i, tc := range []struct {
+		want internal.EnvVar
+		ok   bool
+	}{
+		{want: "APP_DB_HOST", ok: true},
+		{want: "DATABASE_URL", ok: true},
+		{},
+		{},
+	} {
+		f := typ.Fields()[i]
+		t.Run(f.Name(), func(t *testing.T) {
+			got, ok := internal.FieldEnvVar(f, "app_")
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
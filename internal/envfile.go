@@ -0,0 +1,70 @@
+package internal
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// EnvFileSuffix is appended to the environment variable name to read the value from the file,
+// e.g. DB_PASSWORD_FILE=/run/secrets/db for DB_PASSWORD.
+const EnvFileSuffix = "_FILE"
+
+// EnvFileMode is the fields whose values can be read from the files by the environment variables with [EnvFileSuffix].
+type EnvFileMode int
+
+const (
+	// EnvFileNone disables [EnvFileSuffix].
+	EnvFileNone EnvFileMode = iota
+	// EnvFileAll enables [EnvFileSuffix] for all fields.
+	EnvFileAll
+	// EnvFileSecret enables [EnvFileSuffix] only for the fields with the secret tag.
+	EnvFileSecret
+)
+
+func (m EnvFileMode) enabled(s StructField) bool {
+	switch m {
+	case EnvFileAll:
+		return true
+	case EnvFileSecret:
+		return s.Tag().Secret()
+	default:
+		return false
+	}
+}
+
+// LookupFieldEnv retrieves the value of the environment variable of s, see [FieldEnvVar].
+//
+// If the environment variable is absent and mode enables s, the value is the content of the file
+// named by the environment variable with [EnvFileSuffix], without the trailing newline.
+// name is the environment variable that has the value.
+// ok is false if there is no value.
+func LookupFieldEnv(
+	s StructField,
+	envPrefix string,
+	mode EnvFileMode,
+	lookup func(string) (string, bool),
+) (value string, name EnvVar, ok bool, err error) {
+	e, ok := FieldEnvVar(s, envPrefix)
+	if !ok {
+		return "", "", false, nil
+	}
+	if v, ok := e.Lookup(lookup); ok {
+		return v, e, true, nil
+	}
+	if !mode.enabled(s) {
+		return "", "", false, nil
+	}
+	f := e + EnvFileSuffix
+	path, ok := f.Lookup(lookup)
+	if !ok {
+		return "", "", false, nil
+	}
+	b, err := os.ReadFile(path)
+	if err != nil {
+		return "", "", false, fmt.Errorf("%w: %s: %w", ErrStructConfig, f, err)
+	}
+	v := strings.TrimSuffix(string(b), "\n")
+	v = strings.TrimSuffix(v, "\r")
+	return v, f, true, nil
+}
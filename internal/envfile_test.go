@@ -0,0 +1,184 @@
+package internal_test
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLookupFieldEnv(t *testing.T) {
+	type T struct {
+		Password string `name:"db_password" secret:"true"`
+		User     string `name:"db_user"`
+		Disabled string `name:"disabled" env:"-" secret:"true"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+	fields := map[string]internal.StructField{}
+	for _, f := range typ.Fields() {
+		fields[f.Name()] = f
+	}
+
+	dir := t.TempDir()
+	write := func(name, content string) string {
+		path := filepath.Join(dir, name)
+		assert.Nil(t, os.WriteFile(path, []byte(content), 0o600))
+		return path
+	}
+	password := write("password", "hunter2\n")
+	crlf := write("crlf", "hunter2\r\n\n")
+	user := write("user", "root")
+
+	for _, tc := range []struct {
+		title   string
+		field   string
+		mode    internal.EnvFileMode
+		environ []string
+		want    string
+		name    internal.EnvVar
+		ok      bool
+	}{
+		{
+			title:   "plain variable",
+			field:   "Password",
+			mode:    internal.EnvFileAll,
+			environ: []string{"DB_PASSWORD=plain", "DB_PASSWORD_FILE=" + password},
+			want:    "plain",
+			name:    "DB_PASSWORD",
+			ok:      true,
+		},
+		{
+			title:   "file",
+			field:   "Password",
+			mode:    internal.EnvFileAll,
+			environ: []string{"DB_PASSWORD_FILE=" + password},
+			want:    "hunter2",
+			name:    "DB_PASSWORD_FILE",
+			ok:      true,
+		},
+		{
+			title:   "trim only the trailing newline",
+			field:   "Password",
+			mode:    internal.EnvFileAll,
+			environ: []string{"DB_PASSWORD_FILE=" + crlf},
+			want:    "hunter2\r\n",
+			name:    "DB_PASSWORD_FILE",
+			ok:      true,
+		},
+		{
+			title:   "disabled",
+			field:   "Password",
+			mode:    internal.EnvFileNone,
+			environ: []string{"DB_PASSWORD_FILE=" + password},
+		},
+		{
+			title:   "secret only",
+			field:   "Password",
+			mode:    internal.EnvFileSecret,
+			environ: []string{"DB_PASSWORD_FILE=" + password},
+			want:    "hunter2",
+			name:    "DB_PASSWORD_FILE",
+			ok:      true,
+		},
+		{
+			title:   "secret only not secret",
+			field:   "User",
+			mode:    internal.EnvFileSecret,
+			environ: []string{"DB_USER_FILE=" + user},
+		},
+		{
+			title:   "not secret",
+			field:   "User",
+			mode:    internal.EnvFileAll,
+			environ: []string{"DB_USER_FILE=" + user},
+			want:    "root",
+			name:    "DB_USER_FILE",
+			ok:      true,
+		},
+		{
+			title:   "no env",
+			field:   "Disabled",
+			mode:    internal.EnvFileAll,
+			environ: []string{"DISABLED_FILE=" + password},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, name, ok, err := internal.LookupFieldEnv(
+				fields[tc.field], "", tc.mode, internal.EnvironLookup(tc.environ),
+			)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.ok, ok)
+			assert.Equal(t, tc.want, got)
+			assert.Equal(t, tc.name, name)
+		})
+	}
+
+	t.Run("unreadable", func(t *testing.T) {
+		_, _, _, err := internal.LookupFieldEnv(
+			fields["Password"], "", internal.EnvFileAll,
+			internal.EnvironLookup([]string{"DB_PASSWORD_FILE=" + filepath.Join(dir, "missing")}),
+		)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorIs(t, err, fs.ErrNotExist)
+		assert.ErrorContains(t, err, "DB_PASSWORD_FILE: open ")
+	})
+}
+
+func TestEnvReceptorEnvFile(t *testing.T) {
+	type T struct {
+		Password string `name:"password" secret:"true"`
+		Port     int    `name:"port" default:"80"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	path := filepath.Join(t.TempDir(), "password")
+	assert.Nil(t, os.WriteFile(path, []byte("hunter2\n"), 0o600))
+
+	var (
+		got   T
+		found = map[string]string{}
+	)
+	r, err := internal.EnvReceptor(
+		&got, "app_", internal.EnvFileSecret,
+		internal.EnvironLookup([]string{"APP_PASSWORD_FILE=" + path}), nil, nil, nil,
+		func(s internal.StructField, v string, name internal.EnvVar) {
+			found[s.Name()] = name.String() + "=" + v
+		},
+	)
+	assert.Nil(t, err)
+	assert.Nil(t, typ.Accept(r))
+	assert.Equal(t, T{
+		Password: "hunter2",
+		Port:     80,
+	}, got)
+	assert.Equal(t, map[string]string{
+		"Password": "APP_PASSWORD_FILE=hunter2",
+	}, found, "default is not found")
+}
+
+func TestEnvReceptorEnvFileNotExpanded(t *testing.T) {
+	type T struct {
+		Password string `name:"password" secret:"true"`
+		Home     string `name:"home"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	path := filepath.Join(t.TempDir(), "password")
+	assert.Nil(t, os.WriteFile(path, []byte("pa$$word$HOME\n"), 0o600))
+
+	lookup := internal.EnvironLookup([]string{"PASSWORD_FILE=" + path, "HOME=$$HOME"})
+	var got T
+	r, err := internal.EnvReceptor(&got, "", internal.EnvFileSecret, lookup, nil, nil, internal.Expander(lookup), nil)
+	assert.Nil(t, err)
+	assert.Nil(t, typ.Accept(r))
+	assert.Equal(t, T{
+		Password: "pa$$word$HOME",
+		Home:     "$HOME",
+	}, got)
+}
@@ -3,22 +3,49 @@ package internal
 import "reflect"
 
 // EnvReceptor sets environment variable value to the struct field.
+// The environment variable is [FieldEnvVar].
+// The environment variables are retrieved by lookup, [os.LookupEnv] if lookup is nil.
+// envFile enables the files named by the environment variables with [EnvFileSuffix], see [LookupFieldEnv].
+// The environment variable values and the default tag values are expanded by expand if expand is not nil,
+// see [Expander]. The contents of the files by envFile are not expanded.
+// found is called with the value to set and the variable name that has it, see [LookupFieldEnv],
+// when the value is found in the environment variables, not by default tag, if found is not nil.
 //
 // ptr should be a pointer of struct.
 func EnvReceptor(
 	ptr any,
+	envPrefix string,
+	envFile EnvFileMode,
+	lookup func(string) (string, bool),
 	anyCallback func(StructField, string, func() reflect.Value) error,
+	registry *Registry,
+	expand func(string) (string, error),
+	found func(s StructField, value string, name EnvVar),
 ) (*PairsReceptor, error) {
 	get := func(s StructField) (string, error) {
-		name, ok := s.Tag().Name()
-		if !ok {
+		if _, ok := s.Tag().Name(); !ok {
 			// ignore the field
 			return "", ErrSkipParse
 		}
-		if v, ok := NewEnvVar(name).Get(); ok {
+		switch v, name, ok, err := LookupFieldEnv(s, envPrefix, envFile, lookup); {
+		case err != nil:
+			return "", err
+		case ok:
+			if e, _ := FieldEnvVar(s, envPrefix); name == e {
+				// the content of the file is not expanded
+				if v, err = expandValue(s, v, expand); err != nil {
+					return "", err
+				}
+			}
+			if found != nil {
+				found(s, v, name)
+			}
 			return v, nil
 		}
-		if v, ok := s.Tag().Default(); ok {
+		switch v, ok, err := defaultValue(s, expand); {
+		case err != nil:
+			return "", err
+		case ok:
 			return v, nil
 		}
 		return "", ErrSkipParse
@@ -29,5 +56,6 @@ func EnvReceptor(
 		get,
 		NewConv(),
 		anyCallback,
+		registry,
 	)
 }
@@ -19,6 +19,7 @@ func TestEnvReceptor(t *testing.T) {
 		S         string  `name:"es" default:"str"`
 		NoDefault int     `name:"eno_default"`
 		Slice     []int   `name:"eslice"`
+		Any       [][]int `name:"eany"`
 		Ignore    []int   `name:"-"`
 	}
 
@@ -28,7 +29,8 @@ func TestEnvReceptor(t *testing.T) {
 		"EU": "10",
 		"EF": "1.1",
 		// ENO_DEFAULT is not defined
-		"ESLICE": "[1,2]",
+		"ESLICE": "1,2",
+		"EANY":   "[[1],[2]]",
 		"IGNORE": "[1]",
 	}
 	for k, v := range envs {
@@ -47,20 +49,27 @@ func TestEnvReceptor(t *testing.T) {
 		F:     1.1,
 		S:     "str",
 		Slice: []int{1, 2},
+		Any:   [][]int{{1}, {2}},
 	}
 
 	var got T
 
 	r, err := internal.EnvReceptor(
 		&got,
+		"",
+		internal.EnvFileNone,
+		nil,
 		func(_ internal.StructField, v string, fv func() reflect.Value) error {
-			var xs []int
+			var xs [][]int
 			if err := json.Unmarshal([]byte(v), &xs); err != nil {
 				return err
 			}
 			fv().Set(reflect.ValueOf(xs))
 			return nil
 		},
+		nil,
+		nil,
+		nil,
 	)
 
 	assert.Nil(t, err)
@@ -71,3 +80,56 @@ func TestEnvReceptor(t *testing.T) {
 	assert.Nil(t, typ.Accept(r))
 	assert.Equal(t, want, got)
 }
+
+func TestEnvReceptorPointer(t *testing.T) {
+	type T struct {
+		I         *int  `name:"ep_i"`
+		B         *bool `name:"ep_b" default:"true"`
+		NoDefault *int  `name:"ep_no_default"`
+	}
+
+	os.Setenv("EP_I", "0")
+	defer os.Unsetenv("EP_I")
+
+	var got T
+	r, err := internal.EnvReceptor(&got, "", internal.EnvFileNone, nil, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	typ, err := internal.NewType(got, "")
+	assert.Nil(t, err)
+
+	assert.Nil(t, typ.Accept(r))
+	if assert.NotNil(t, got.I) {
+		assert.Equal(t, 0, *got.I)
+	}
+	if assert.NotNil(t, got.B) {
+		assert.True(t, *got.B)
+	}
+	assert.Nil(t, got.NoDefault)
+}
+
+func TestEnvReceptorPrefix(t *testing.T) {
+	type T struct {
+		Port int    `name:"port"`
+		Host string `name:"db.host" default:"localhost"`
+	}
+
+	lookup := internal.EnvironLookup([]string{
+		"PORT=80",
+		"MYAPP_PORT=8080",
+		"DB_HOST=db",
+	})
+
+	var got T
+	r, err := internal.EnvReceptor(&got, "myapp_", internal.EnvFileNone, lookup, nil, nil, nil, nil)
+	assert.Nil(t, err)
+
+	typ, err := internal.NewType(got, "")
+	assert.Nil(t, err)
+
+	assert.Nil(t, typ.Accept(r))
+	assert.Equal(t, T{
+		Port: 8080,
+		Host: "localhost",
+	}, got)
+}
@@ -9,6 +9,8 @@ var (
 	ErrStructConfig     = errors.New("StructConfig")
 	ErrNotStruct        = errors.New("NotStruct")
 	ErrNotStructPointer = errors.New("NotStructPointer")
+	ErrRequired         = errors.New("Required")
+	ErrValidation       = errors.New("Validation")
 )
 
 func Errorf(format string, v ...any) error {
@@ -0,0 +1,179 @@
+package internal
+
+import (
+	"errors"
+	"os"
+	"strings"
+)
+
+// Expand expands the variables in s by lookup like the shell:
+//   - $VAR, ${VAR}: the value of VAR, an empty string if VAR is unset
+//   - ${VAR:-word}: word if VAR is unset or empty
+//   - ${VAR-word}: word if VAR is unset
+//   - $$: $
+//
+// word is also expanded. $ not followed by a name, { or $ is left as is.
+func Expand(s string, lookup func(string) (string, bool)) (string, error) {
+	e := &expander{
+		src:    s,
+		lookup: lookup,
+	}
+	return e.expand(false)
+}
+
+// Expander returns [Expand] by lookup, [os.LookupEnv] if lookup is nil.
+func Expander(lookup func(string) (string, bool)) func(string) (string, error) {
+	if lookup == nil {
+		lookup = os.LookupEnv
+	}
+	return func(s string) (string, error) {
+		return Expand(s, lookup)
+	}
+}
+
+// ExpandEscape escapes $ in s so that [Expand] returns s as is.
+func ExpandEscape(s string) string {
+	return strings.ReplaceAll(s, "$", "$$")
+}
+
+var (
+	errExpandUnterminated = errors.New("expand: unterminated ${")
+	errExpandInvalidName  = errors.New("expand: invalid variable name in ${")
+)
+
+type expander struct {
+	src    string
+	pos    int
+	lookup func(string) (string, bool)
+}
+
+func (e *expander) eof() bool { return e.pos >= len(e.src) }
+
+// expand expands until the end of src, or until } if inBrace.
+func (e *expander) expand(inBrace bool) (string, error) {
+	var b strings.Builder
+	for !e.eof() {
+		c := e.src[e.pos]
+		if inBrace && c == '}' {
+			return b.String(), nil
+		}
+		e.pos++
+		if c != '$' {
+			b.WriteByte(c)
+			continue
+		}
+		if e.eof() {
+			b.WriteByte('$')
+			break
+		}
+		switch n := e.src[e.pos]; {
+		case n == '$':
+			e.pos++
+			b.WriteByte('$')
+		case n == '{':
+			e.pos++
+			v, err := e.braced()
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(v)
+		case isExpandNameStart(n):
+			v, _ := e.lookup(e.name())
+			b.WriteString(v)
+		default:
+			b.WriteByte('$')
+		}
+	}
+	if inBrace {
+		return "", errExpandUnterminated
+	}
+	return b.String(), nil
+}
+
+// braced expands the rest of ${...} and consumes }.
+func (e *expander) braced() (string, error) {
+	if e.eof() || !isExpandNameStart(e.src[e.pos]) {
+		return "", errExpandInvalidName
+	}
+	name := e.name()
+	if e.eof() {
+		return "", errExpandUnterminated
+	}
+	v, ok := e.lookup(name)
+	switch {
+	case e.src[e.pos] == '}':
+		e.pos++
+		return v, nil
+	case strings.HasPrefix(e.src[e.pos:], ":-"):
+		e.pos += 2
+		word, err := e.word()
+		if err != nil {
+			return "", err
+		}
+		if ok && v != "" {
+			return v, nil
+		}
+		return word, nil
+	case e.src[e.pos] == '-':
+		e.pos++
+		word, err := e.word()
+		if err != nil {
+			return "", err
+		}
+		if ok {
+			return v, nil
+		}
+		return word, nil
+	default:
+		return "", errExpandInvalidName
+	}
+}
+
+func (e *expander) word() (string, error) {
+	w, err := e.expand(true)
+	if err != nil {
+		return "", err
+	}
+	e.pos++ // }
+	return w, nil
+}
+
+func (e *expander) name() string {
+	start := e.pos
+	for !e.eof() && isExpandNameChar(e.src[e.pos]) {
+		e.pos++
+	}
+	return e.src[start:e.pos]
+}
+
+func isExpandNameStart(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c == '_'
+}
+
+func isExpandNameChar(c byte) bool {
+	return isExpandNameStart(c) || c >= '0' && c <= '9'
+}
+
+// expandValue expands v of s by expand if expand is not nil.
+func expandValue(s StructField, v string, expand func(string) (string, error)) (string, error) {
+	if expand == nil {
+		return v, nil
+	}
+	x, err := expand(v)
+	if err != nil {
+		return "", Errorf("%s: %v", s.Name(), err)
+	}
+	return x, nil
+}
+
+// defaultValue returns the default tag value of s expanded by expand if expand is not nil.
+// ok is false if s has no default tag or the default tag value references other fields,
+// see [ResolveDefaultRefs].
+func defaultValue(s StructField, expand func(string) (string, error)) (value string, ok bool, err error) {
+	v, ok := s.Tag().Default()
+	if !ok || HasDefaultRef(v) {
+		return "", false, nil
+	}
+	v, err = expandValue(s, v, expand)
+	return v, true, err
+}
@@ -0,0 +1,148 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExpand(t *testing.T) {
+	lookup := internal.EnvironLookup([]string{
+		"HOME=/home/user",
+		"EMPTY=",
+		"DIR=/tmp",
+	})
+
+	for _, tc := range []struct {
+		title string
+		input string
+		want  string
+	}{
+		{title: "empty", input: "", want: ""},
+		{title: "no variables", input: "a b", want: "a b"},
+		{title: "braced", input: "${HOME}/.cache/app", want: "/home/user/.cache/app"},
+		{title: "unbraced", input: "$HOME/.cache", want: "/home/user/.cache"},
+		{title: "unset", input: "x${UNSET}y$UNSET", want: "xy"},
+		{title: "fallback unset", input: "${XDG_RUNTIME_DIR:-/tmp}/app.sock", want: "/tmp/app.sock"},
+		{title: "fallback empty", input: "${EMPTY:-x}", want: "x"},
+		{title: "fallback set", input: "${HOME:-x}", want: "/home/user"},
+		{title: "unset fallback empty", input: "${EMPTY-x}", want: ""},
+		{title: "unset fallback unset", input: "${UNSET-x}", want: "x"},
+		{title: "nested fallback", input: "${UNSET:-${DIR}/app}", want: "/tmp/app"},
+		{title: "empty fallback", input: "${UNSET:-}", want: ""},
+		{title: "escape", input: "$${HOME} $$HOME $$", want: "${HOME} $HOME $"},
+		{title: "literal dollar", input: "$ 1 $1 a$", want: "$ 1 $1 a$"},
+		{title: "name chars", input: "$HOME_1.$DIR-x", want: "./tmp-x"},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := internal.Expand(tc.input, lookup)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestExpandError(t *testing.T) {
+	lookup := internal.EnvironLookup(nil)
+
+	for _, tc := range []struct {
+		title string
+		input string
+		want  string
+	}{
+		{title: "unterminated", input: "${HOME", want: "unterminated ${"},
+		{title: "unterminated fallback", input: "${HOME:-x", want: "unterminated ${"},
+		{title: "unterminated nested", input: "${A:-${B}", want: "unterminated ${"},
+		{title: "empty name", input: "${}", want: "invalid variable name in ${"},
+		{title: "invalid name", input: "${1A}", want: "invalid variable name in ${"},
+		{title: "invalid operator", input: "${A:=x}", want: "invalid variable name in ${"},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := internal.Expand(tc.input, lookup)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func TestExpandEscape(t *testing.T) {
+	const s = "a$b${c}$$"
+	got, err := internal.Expand(internal.ExpandEscape(s), internal.EnvironLookup([]string{"b=x", "c=y"}))
+	assert.Nil(t, err)
+	assert.Equal(t, s, got)
+}
+
+func TestReceptorExpand(t *testing.T) {
+	type T struct {
+		Dir    string   `name:"dir" default:"${HOME}/.cache"`
+		Sock   string   `name:"sock" default:"${RUNTIME_DIR:-/tmp}/app.sock"`
+		Hosts  []string `name:"hosts" default:"${HOST},b"`
+		Broken string   `name:"broken"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	lookup := internal.EnvironLookup([]string{
+		"HOME=/home/user",
+		"HOST=a",
+		"BROKEN=${X",
+	})
+	expand := internal.Expander(lookup)
+
+	t.Run("default", func(t *testing.T) {
+		var got T
+		r, err := internal.DefaultReceptor(&got, nil, nil, expand)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			Dir:   "/home/user/.cache",
+			Sock:  "/tmp/app.sock",
+			Hosts: []string{"a", "b"},
+		}, got)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		var got T
+		r, err := internal.EnvReceptor(
+			&got, "", internal.EnvFileNone,
+			internal.EnvironLookup([]string{"DIR=$HOME/dir", "SOCK=$$HOME"}), nil, nil, expand, nil,
+		)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			Dir:   "/home/user/dir",
+			Sock:  "$HOME",
+			Hosts: []string{"a", "b"},
+		}, got)
+	})
+
+	t.Run("env error", func(t *testing.T) {
+		var got T
+		r, err := internal.EnvReceptor(&got, "", internal.EnvFileNone, lookup, nil, nil, expand, nil)
+		assert.Nil(t, err)
+		err = typ.Accept(r)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorContains(t, err, "Broken: expand: unterminated ${")
+	})
+
+	t.Run("disabled", func(t *testing.T) {
+		var got T
+		r, err := internal.DefaultReceptor(&got, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, "${HOME}/.cache", got.Dir)
+	})
+}
+
+func TestMergerWithExpand(t *testing.T) {
+	type T struct {
+		Dir string `name:"dir" default:"${HOME}/.cache"`
+	}
+	m := internal.NewMerger[T](nil, nil, "", nil).
+		WithExpand(internal.Expander(internal.EnvironLookup([]string{"HOME=/home/user"})))
+
+	// the right is the expanded default
+	got, err := m.Merge(T{Dir: "/left"}, T{Dir: "/home/user/.cache"})
+	assert.Nil(t, err)
+	assert.Equal(t, T{Dir: "/left"}, got)
+}
@@ -0,0 +1,156 @@
+package internal
+
+import (
+	"encoding"
+	"encoding/json"
+	"fmt"
+	"io"
+	"reflect"
+	"slices"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// Explanation is the effective configuration of a field.
+type Explanation struct {
+	// Field is the Go field name, e.g. "DB.Host".
+	Field string `json:"field"`
+	// Flag is the command-line flag name without "--".
+	Flag string `json:"flag,omitempty"`
+	// Env is the environment variable name, see [FieldEnvVar].
+	Env string `json:"env,omitempty"`
+	// Value is the formatted value of the field, see [FormatValue].
+	Value string `json:"value"`
+	// Default is the default tag value.
+	Default string `json:"default,omitempty"`
+	// Source is where the value came from.
+	Source string `json:"source,omitempty"`
+	// Usage is the usage tag value.
+	Usage string `json:"usage,omitempty"`
+}
+
+// Explain returns the explanations of the fields that have the name tag.
+// envPrefix is for the environment variable names, see [FieldEnvVar].
+// The values and the default tag values of the fields with the secret tag are masked, see [RedactValue].
+//
+// ptr should be a pointer of struct.
+func Explain(ptr any, typ *Type, envPrefix string) ([]*Explanation, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, JoinErrors(ErrNotStructPointer)
+	}
+
+	xs := []*Explanation{}
+	for _, f := range typ.Fields() {
+		if _, ok := f.Tag().Name(); !ok {
+			continue
+		}
+		value, err := FormatValue(f, v.Elem().FieldByIndex(f.Index()))
+		if err != nil {
+			return nil, Errorf("%s: %v", f.Name(), err)
+		}
+		x := &Explanation{
+			Field: f.Name(),
+			Value: RedactValue(f, value),
+			Usage: f.Tag().Usage(),
+		}
+		x.Flag, _ = f.Tag().Flag()
+		if env, ok := FieldEnvVar(f, envPrefix); ok {
+			x.Env = env.String()
+		}
+		if d, ok := f.Tag().Default(); ok {
+			x.Default = RedactValue(f, d)
+		}
+		xs = append(xs, x)
+	}
+	return xs, nil
+}
+
+// FormatValue formats the value of s in the same form as the default tag value:
+// the elements of slices and maps are joined by the sep tag value,
+// [time.Time] is formatted by the layout tag value
+// and [encoding.TextMarshaler] is formatted by itself.
+// A nil pointer is an empty string.
+func FormatValue(s StructField, v reflect.Value) (string, error) {
+	if v.Kind() == reflect.Pointer && v.Type() != locationType {
+		if v.IsNil() {
+			return "", nil
+		}
+		v = v.Elem()
+	}
+	switch v.Kind() {
+	case reflect.Slice:
+		xs := make([]string, v.Len())
+		for i := range v.Len() {
+			x, err := formatScalar(s, v.Index(i))
+			if err != nil {
+				return "", err
+			}
+			xs[i] = x
+		}
+		return strings.Join(xs, s.Tag().Sep()), nil
+	case reflect.Map:
+		xs := make([]string, 0, v.Len())
+		iter := v.MapRange()
+		for iter.Next() {
+			x, err := formatScalar(s, iter.Value())
+			if err != nil {
+				return "", err
+			}
+			xs = append(xs, fmt.Sprintf("%v=%s", iter.Key(), x))
+		}
+		slices.Sort(xs)
+		return strings.Join(xs, s.Tag().Sep()), nil
+	default:
+		return formatScalar(s, v)
+	}
+}
+
+func formatScalar(s StructField, v reflect.Value) (string, error) {
+	switch v.Type() {
+	case timeType:
+		return v.Interface().(time.Time).Format(s.Tag().Layout()), nil
+	case locationType:
+		if v.IsNil() {
+			return "", nil
+		}
+	}
+	if v.CanInterface() {
+		if x, ok := v.Interface().(encoding.TextMarshaler); ok {
+			b, err := x.MarshalText()
+			if err != nil {
+				return "", err
+			}
+			return string(b), nil
+		}
+	}
+	return fmt.Sprint(v), nil
+}
+
+// WriteExplanationTable writes the explanations as a table with a header.
+func WriteExplanationTable(w io.Writer, xs []*Explanation) error {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	if _, err := fmt.Fprintln(tw, "FIELD\tFLAG\tENV\tVALUE\tDEFAULT\tSOURCE\tUSAGE"); err != nil {
+		return err
+	}
+	for _, x := range xs {
+		flag := x.Flag
+		if flag != "" {
+			flag = "--" + flag
+		}
+		if _, err := fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			x.Field, flag, x.Env, x.Value, x.Default, x.Source, x.Usage,
+		); err != nil {
+			return err
+		}
+	}
+	return tw.Flush()
+}
+
+// WriteExplanationJSON writes the explanations as a JSON array.
+func WriteExplanationJSON(w io.Writer, xs []*Explanation) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(xs)
+}
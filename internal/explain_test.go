@@ -0,0 +1,86 @@
+package internal_test
+
+import (
+	"bytes"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestExplain(t *testing.T) {
+	type DB struct {
+		Host string `name:"host" default:"localhost" usage:"database host"`
+	}
+	type T struct {
+		Port    int            `name:"port" default:"80"`
+		Hosts   []string       `name:"hosts" sep:";"`
+		Labels  map[string]int `name:"labels"`
+		Timeout time.Duration  `name:"timeout" default:"1s"`
+		At      time.Time      `name:"at" layout:"2006-01-02"`
+		Level   slog.Level     `name:"level"`
+		P       *int           `name:"p"`
+		Env     string         `name:"env_only" flag:"-" env:"ONLY"`
+		Ignore  int
+		DB      DB                `name:"db"`
+		Empty   map[string]string `name:"empty"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	v := T{
+		Port:    8080,
+		Hosts:   []string{"a", "b"},
+		Labels:  map[string]int{"b": 2, "a": 1},
+		Timeout: time.Minute,
+		At:      time.Date(2026, 1, 2, 0, 0, 0, 0, time.UTC),
+		Level:   slog.LevelWarn,
+		Env:     "e",
+		Ignore:  1,
+		DB: DB{
+			Host: "db",
+		},
+	}
+	got, err := internal.Explain(&v, typ, "app_")
+	assert.Nil(t, err)
+	assert.Equal(t, []*internal.Explanation{
+		{Field: "Port", Flag: "port", Env: "APP_PORT", Value: "8080", Default: "80"},
+		{Field: "Hosts", Flag: "hosts", Env: "APP_HOSTS", Value: "a;b"},
+		{Field: "Labels", Flag: "labels", Env: "APP_LABELS", Value: "a=1,b=2"},
+		{Field: "Timeout", Flag: "timeout", Env: "APP_TIMEOUT", Value: "1m0s", Default: "1s"},
+		{Field: "At", Flag: "at", Env: "APP_AT", Value: "2026-01-02"},
+		{Field: "Level", Flag: "level", Env: "APP_LEVEL", Value: "WARN"},
+		{Field: "P", Flag: "p", Env: "APP_P", Value: ""},
+		{Field: "Env", Env: "ONLY", Value: "e"},
+		{Field: "DB.Host", Flag: "db.host", Env: "APP_DB_HOST", Value: "db", Default: "localhost", Usage: "database host"},
+		{Field: "Empty", Flag: "empty", Env: "APP_EMPTY", Value: ""},
+	}, got)
+}
+
+func TestWriteExplanation(t *testing.T) {
+	xs := []*internal.Explanation{
+		{Field: "Port", Flag: "port", Env: "PORT", Value: "8080", Default: "80", Source: "flag", Usage: "listen port"},
+		{Field: "Env", Env: "ONLY", Value: "e", Source: "env", Usage: "env only"},
+	}
+
+	t.Run("table", func(t *testing.T) {
+		var b bytes.Buffer
+		assert.Nil(t, internal.WriteExplanationTable(&b, xs))
+		assert.Equal(t, `FIELD  FLAG    ENV   VALUE  DEFAULT  SOURCE  USAGE
+Port   --port  PORT  8080   80       flag    listen port
+Env            ONLY  e               env     env only
+`, b.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var b bytes.Buffer
+		assert.Nil(t, internal.WriteExplanationJSON(&b, xs))
+		assert.JSONEq(t, `[
+  {"field":"Port","flag":"port","env":"PORT","value":"8080","default":"80","source":"flag","usage":"listen port"},
+  {"field":"Env","env":"ONLY","value":"e","source":"env","usage":"env only"}
+]`, b.String())
+	})
+}
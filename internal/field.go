@@ -1,6 +1,6 @@
 package internal
 
-//go:generate go tool dataclass -type StructField -field "Name string|Kind reflect.Kind|Tag *Tag" -output structfield_dataclass_generated.go
+//go:generate go tool dataclass -type StructField -field "Name string|Kind reflect.Kind|FieldType reflect.Type|Tag *Tag|Index []int" -output structfield_dataclass_generated.go
 
 // Receptor accepts [StructField].
 type Receptor interface {
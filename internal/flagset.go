@@ -4,7 +4,7 @@ package internal
 type FlagSetFunc[T any] func(name string, defaultValue T, usage string) error
 
 // SetFlag defines a flag.
-//   - name: name tag value
+//   - name: [Tag.Flag]
 //   - default value: v
 //   - usage: usage tag value
 func (f FlagSetFunc[T]) SetFlag(s StructField, v T) error {
@@ -12,7 +12,7 @@ func (f FlagSetFunc[T]) SetFlag(s StructField, v T) error {
 		return nil
 	}
 
-	name, ok := s.Tag().Name()
+	name, ok := s.Tag().Flag()
 	if !ok {
 		// ignore the field
 		return nil
@@ -21,9 +21,14 @@ func (f FlagSetFunc[T]) SetFlag(s StructField, v T) error {
 	return f(name, v, usage)
 }
 
-func FlagSetReceptor(typedReceptor TypedReceptor) *PairsReceptor {
+// FlagSetReceptor defines the flags by typedReceptor with the default tag values.
+// The default tag value is expanded by expand if expand is not nil, see [Expander].
+func FlagSetReceptor(typedReceptor TypedReceptor, expand func(string) (string, error)) *PairsReceptor {
 	get := func(s StructField) (string, error) {
-		if v, ok := s.Tag().Default(); ok {
+		switch v, ok, err := defaultValue(s, expand); {
+		case err != nil:
+			return "", err
+		case ok:
 			return v, nil
 		}
 		// tell to pass default value when default tag value is missing
@@ -0,0 +1,108 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"unicode/utf8"
+)
+
+// DecodeINI reads an INI file into [Document].
+// The values are wrapped by [*DocumentNode] to hold the line and the column.
+//
+// The keys in the section [db] are for "db.key", the section [db.pool] is for "db.pool.key".
+// The keys before the first section are at the top level.
+// Lines starting with ';' or '#' are comments, and so are ';' and '#' after a space in an unquoted value.
+// The values enclosed in double or single quotes are unquoted as is.
+// A later key overrides the same key in the same section.
+func DecodeINI(r io.Reader) (Document, error) {
+	root := Document{}
+	section := root
+	scanner := bufio.NewScanner(r)
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		line := scanner.Text()
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		trimmed := strings.TrimSpace(line)
+		switch {
+		case trimmed == "", strings.HasPrefix(trimmed, ";"), strings.HasPrefix(trimmed, "#"):
+			continue
+		case strings.HasPrefix(trimmed, "["):
+			end := strings.Index(trimmed, "]")
+			if end < 0 {
+				return nil, Errorf("decode ini: line %d: unterminated section", lineNumber)
+			}
+			if rest := strings.TrimSpace(trimmed[end+1:]); rest != "" && !strings.HasPrefix(rest, ";") && !strings.HasPrefix(rest, "#") {
+				return nil, Errorf("decode ini: line %d: unexpected %s", lineNumber, rest)
+			}
+			name := strings.TrimSpace(trimmed[1:end])
+			if name == "" {
+				return nil, Errorf("decode ini: line %d: empty section", lineNumber)
+			}
+			if v, ok := root[name]; ok {
+				d, ok := asDocument(v)
+				if !ok {
+					return nil, Errorf("decode ini: line %d: %s is not a section", lineNumber, name)
+				}
+				section = d
+				continue
+			}
+			section = Document{}
+			root[name] = &DocumentNode{
+				Value:  section,
+				Line:   lineNumber,
+				Column: utf8.RuneCountInString(line[:strings.Index(line, "[")]) + 1,
+			}
+			continue
+		}
+
+		i := strings.IndexAny(line, "=:")
+		if i < 0 {
+			return nil, Errorf("decode ini: line %d: expected = or :", lineNumber)
+		}
+		key := strings.TrimSpace(line[:i])
+		if key == "" {
+			return nil, Errorf("decode ini: line %d: empty key", lineNumber)
+		}
+		rawValue := line[i+1:]
+		column := utf8.RuneCountInString(line[:i+1]) + 1 + len(rawValue) - len(strings.TrimLeft(rawValue, " \t"))
+		value, err := unquoteINIValue(strings.TrimSpace(rawValue))
+		if err != nil {
+			return nil, Errorf("decode ini: line %d: %s: %v", lineNumber, key, err)
+		}
+		section[key] = &DocumentNode{
+			Value:  value,
+			Line:   lineNumber,
+			Column: column,
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, JoinErrors(Errorf("decode ini"), err)
+	}
+	return root, nil
+}
+
+func unquoteINIValue(s string) (string, error) {
+	if s == "" {
+		return "", nil
+	}
+	if q := s[0]; q == '"' || q == '\'' {
+		end := strings.IndexByte(s[1:], q)
+		if end < 0 {
+			return "", errors.New("unterminated quoted value")
+		}
+		if rest := strings.TrimSpace(s[end+2:]); rest != "" && !strings.HasPrefix(rest, ";") && !strings.HasPrefix(rest, "#") {
+			return "", fmt.Errorf("unexpected %s", rest)
+		}
+		return s[1 : end+1], nil
+	}
+	for i := 1; i < len(s); i++ {
+		if (s[i] == ';' || s[i] == '#') && (s[i-1] == ' ' || s[i-1] == '\t') {
+			return strings.TrimSpace(s[:i]), nil
+		}
+	}
+	return s, nil
+}
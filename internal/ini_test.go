@@ -0,0 +1,174 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeINI(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		input string
+		want  map[string]any
+	}{
+		{
+			title: "empty",
+			input: "",
+			want:  map[string]any{},
+		},
+		{
+			title: "sections",
+			input: `; comment
+# comment
+top = 1
+
+[db]
+host = localhost ; comment
+port: 5432
+name = "a ; b"
+user = 'root'
+
+[db.pool]
+size=10
+
+[db]
+host = overridden
+`,
+			want: map[string]any{
+				"top": "1",
+				"db": map[string]any{
+					"host": "overridden",
+					"port": "5432",
+					"name": "a ; b",
+					"user": "root",
+				},
+				"db.pool": map[string]any{
+					"size": "10",
+				},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := internal.DecodeINI(strings.NewReader(tc.input))
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, plainDocument(got))
+		})
+	}
+}
+
+func TestDecodeINIError(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		input string
+		want  string
+	}{
+		{
+			title: "unterminated section",
+			input: "[db\n",
+			want:  "line 1: unterminated section",
+		},
+		{
+			title: "empty section",
+			input: "[]\n",
+			want:  "line 1: empty section",
+		},
+		{
+			title: "no separator",
+			input: "a = 1\nb\n",
+			want:  "line 2: expected = or :",
+		},
+		{
+			title: "empty key",
+			input: "= 1\n",
+			want:  "line 1: empty key",
+		},
+		{
+			title: "unterminated quoted value",
+			input: "a = \"x\n",
+			want:  "line 1: a: unterminated quoted value",
+		},
+		{
+			title: "section conflicts with key",
+			input: "a = 1\n[a]\n",
+			want:  "line 2: a is not a section",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := internal.DecodeINI(strings.NewReader(tc.input))
+			assert.ErrorIs(t, err, internal.ErrStructConfig)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func TestINIDocumentReceptor(t *testing.T) {
+	type Pool struct {
+		Size int `name:"size"`
+	}
+	type DB struct {
+		Host string `name:"host"`
+		Port int    `name:"port" default:"5432"`
+		Pool Pool   `name:"pool"`
+	}
+	type T struct {
+		Debug bool  `name:"debug"`
+		Slice []int `name:"slice"`
+		DB    DB    `name:"db"`
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		const input = `debug = true
+slice = 1,2
+
+[db]
+host = localhost
+
+[db.pool]
+size = 10
+`
+		doc, err := internal.DecodeINI(strings.NewReader(input))
+		assert.Nil(t, err)
+
+		var got T
+		r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+		assert.Nil(t, err)
+
+		typ, err := internal.NewType(got, "")
+		assert.Nil(t, err)
+
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			Debug: true,
+			Slice: []int{1, 2},
+			DB: DB{
+				Host: "localhost",
+				Port: 5432,
+				Pool: Pool{
+					Size: 10,
+				},
+			},
+		}, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		const input = `[db]
+port =  x
+`
+		doc, err := internal.DecodeINI(strings.NewReader(input))
+		assert.Nil(t, err)
+
+		var got T
+		r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+		assert.Nil(t, err)
+
+		typ, err := internal.NewType(got, "")
+		assert.Nil(t, err)
+
+		err = typ.Accept(r)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorContains(t, err, "line 2, column 9: DB.Port (db.port): ")
+	})
+}
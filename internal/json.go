@@ -0,0 +1,18 @@
+package internal
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// DecodeJSON decodes a JSON object into [Document].
+// Numbers are decoded into [json.Number] to keep the precision.
+func DecodeJSON(r io.Reader) (Document, error) {
+	dec := json.NewDecoder(r)
+	dec.UseNumber()
+	var d Document
+	if err := dec.Decode(&d); err != nil {
+		return nil, JoinErrors(Errorf("decode json"), err)
+	}
+	return d, nil
+}
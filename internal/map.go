@@ -0,0 +1,71 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// MapKeyValueSeparator separates the key and the value of an element of maps.
+const MapKeyValueSeparator = "="
+
+// IsSupportedMap reports true if t is a map from string to a supported kind.
+func IsSupportedMap(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Map &&
+		t.Key().Kind() == reflect.String && IsSupportedKind(t.Elem().Kind())
+}
+
+// SplitMap splits s like "k1=v1,k2=v2" into a map, sep separates the elements.
+// Empty s is an empty map.
+func SplitMap(s, sep string) (map[string]string, error) {
+	r := map[string]string{}
+	for _, x := range SplitSlice(s, sep) {
+		k, v, ok := strings.Cut(x, MapKeyValueSeparator)
+		if !ok {
+			return nil, Errorf("%s must be formatted as key%svalue", x, MapKeyValueSeparator)
+		}
+		r[k] = v
+	}
+	return r, nil
+}
+
+// SetMap converts m into the elements of the map v by [NewConv] and sets them to v.
+func SetMap(v reflect.Value, m map[string]string) error {
+	var (
+		typ = v.Type()
+		r   = reflect.MakeMapWithSize(typ, len(m))
+	)
+	for k, x := range m {
+		key := reflect.New(typ.Key()).Elem()
+		key.SetString(k)
+		value := reflect.New(typ.Elem()).Elem()
+		if err := ConvertValue(NewConv(), value, x); err != nil {
+			return err
+		}
+		r.SetMapIndex(key, value)
+	}
+	v.Set(r)
+	return nil
+}
+
+// ParseMap converts m into map[string]T.
+func ParseMap[T any](m map[string]string) (map[string]T, error) {
+	var r map[string]T
+	if err := SetMap(reflect.ValueOf(&r).Elem(), m); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func equalMap(left, right reflect.Value) bool {
+	if left.Len() != right.Len() {
+		return false
+	}
+	iter := left.MapRange()
+	for iter.Next() {
+		x := right.MapIndex(iter.Key())
+		if !x.IsValid() || x.Interface() != iter.Value().Interface() {
+			return false
+		}
+	}
+	return true
+}
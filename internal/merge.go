@@ -7,15 +7,18 @@ import "reflect"
 // anyCallback parses "default" tag value and set it.
 // anyEqual reports true if left equals right when kind of arguments are not supported.
 // prefix adds a prefix to "default" tag name.
+// registry converts and compares the values of the registered types first, can be nil.
 func NewMerger[T any](
 	anyCallback func(StructField, string, func() reflect.Value) error,
 	anyEqual func(left, right any) (bool, error),
 	prefix string,
+	registry *Registry,
 ) *Merger[T] {
 	return &Merger[T]{
 		anyCallback: anyCallback,
 		anyEqual:    anyEqual,
 		prefix:      prefix,
+		registry:    registry,
 	}
 }
 
@@ -23,10 +26,19 @@ type Merger[T any] struct {
 	anyCallback func(StructField, string, func() reflect.Value) error
 	anyEqual    func(left, right any) (bool, error)
 	prefix      string
+	registry    *Registry
+	expand      func(string) (string, error)
+}
+
+// WithExpand returns a copy of m that expands the default tag values by expand, see [Expander].
+// expand can be nil to disable the expansion.
+func (m Merger[T]) WithExpand(expand func(string) (string, error)) *Merger[T] {
+	m.expand = expand
+	return &m
 }
 
 func (m Merger[T]) newReceptor(ptr *T) (*PairsReceptor, error) {
-	return DefaultReceptor(ptr, m.anyCallback)
+	return DefaultReceptor(ptr, m.anyCallback, m.registry, m.expand)
 }
 
 func (m Merger[T]) getType() (*Type, error) {
@@ -42,19 +54,53 @@ func (m Merger[T]) equal(left, right any) (bool, error) {
 		return true, nil
 	}
 
+	if eq, ok, err := m.registry.Equal(left, right); ok {
+		return eq, err
+	}
+
 	lType, rType := reflect.TypeOf(left), reflect.TypeOf(right)
 	if lType.Kind() != rType.Kind() {
 		return false, nil
 	}
+	if IsOptional(lType) {
+		lValue, rValue := reflect.ValueOf(left), reflect.ValueOf(right)
+		if lValue.IsNil() || rValue.IsNil() {
+			return lValue.IsNil() == rValue.IsNil(), nil
+		}
+		return m.equal(lValue.Elem().Interface(), rValue.Elem().Interface())
+	}
 	if IsSupportedKind(lType.Kind()) {
 		return left == right, nil
 	}
+	if eq, ok := equalTime(left, right); ok {
+		return eq, nil
+	}
+	if eq, ok := equalText(left, right); ok {
+		return eq, nil
+	}
+	if IsSupportedSlice(lType) && lType == rType {
+		// nil equals empty
+		return equalSlice(reflect.ValueOf(left), reflect.ValueOf(right)), nil
+	}
+	if IsSupportedMap(lType) && lType == rType {
+		// nil equals empty
+		return equalMap(reflect.ValueOf(left), reflect.ValueOf(right)), nil
+	}
 	if eq := m.anyEqual; eq != nil {
 		return eq(left, right)
 	}
 	return false, nil
 }
 
+// isDefault reports true if v equals the default value d.
+// A nil [IsOptional] value is considered to be the default value because it means that the value is not given.
+func (m Merger[T]) isDefault(d, v reflect.Value) (bool, error) {
+	if IsOptional(v.Type()) && v.IsNil() {
+		return true, nil
+	}
+	return m.equal(d.Interface(), v.Interface())
+}
+
 func (m Merger[T]) defaultValue() (T, error) {
 	var value T
 	typ, err := m.getType()
@@ -77,7 +123,47 @@ func (m Merger[T]) defaultValue() (T, error) {
 // Merge values based on the 'default' tag values.
 // For each field, if the right value is not the default, use it; if not, use the left value.
 // If that is also the default, set the default value. Return this instance.
+// A nil pointer is not used because it means that the value is not given.
 func (m Merger[T]) Merge(left, right T) (T, error) {
+	return m.MergeFunc(left, right, nil)
+}
+
+// MergeDecision is the value that [Merger.MergeFunc] takes for a field.
+type MergeDecision int
+
+const (
+	MergeDefault MergeDecision = iota
+	MergeLeft
+	MergeRight
+)
+
+func (d MergeDecision) String() string {
+	switch d {
+	case MergeLeft:
+		return "left"
+	case MergeRight:
+		return "right"
+	default:
+		return "default"
+	}
+}
+
+// MergeFunc is [Merger.Merge] that calls decide with the decision for each field, decide can be nil.
+func (m Merger[T]) MergeFunc(left, right T, decide func(StructField, MergeDecision)) (T, error) {
+	return m.MergeSetFunc(left, right, nil, decide)
+}
+
+// MergeSetFunc is [Merger.MergeFunc] that uses the right value if isSet reports true
+// even if it is the default value, isSet can be nil.
+// isSet tells the fields that the source of right actually set.
+func (m Merger[T]) MergeSetFunc(
+	left, right T,
+	isSet func(StructField) bool,
+	decide func(StructField, MergeDecision),
+) (T, error) {
+	if decide == nil {
+		decide = func(StructField, MergeDecision) {}
+	}
 	v, err := m.defaultValue()
 	if err != nil {
 		return v, err
@@ -96,33 +182,42 @@ func (m Merger[T]) Merge(left, right T) (T, error) {
 			continue
 		}
 
-		name := f.Name()
-		fv := vv.Elem().FieldByName(name)
+		index := f.Index()
+		fv := vv.Elem().FieldByIndex(index)
 
+		if isSet != nil && isSet(f) {
+			// overwrite by the value set explicitly
+			fv.Set(rValue.FieldByIndex(index))
+			decide(f, MergeRight)
+			continue
+		}
 		{
-			rv := rValue.FieldByName(name)
-			ok, err := m.equal(fv.Interface(), rv.Interface())
+			rv := rValue.FieldByIndex(index)
+			ok, err := m.isDefault(fv, rv)
 			if err != nil {
 				return v, err
 			}
 			if !ok {
 				// overwrite by not default value from right
 				fv.Set(rv)
+				decide(f, MergeRight)
 				continue
 			}
 		}
 		{
-			lv := lValue.FieldByName(name)
-			ok, err := m.equal(fv.Interface(), lv.Interface())
+			lv := lValue.FieldByIndex(index)
+			ok, err := m.isDefault(fv, lv)
 			if err != nil {
 				return v, err
 			}
 			if !ok {
 				// overwrite by not default value from left
 				fv.Set(lv)
+				decide(f, MergeLeft)
 				continue
 			}
 		}
+		decide(f, MergeDefault)
 	}
 	return v, nil
 }
@@ -5,6 +5,7 @@ import (
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 
 	"github.com/berquerant/structconfig/internal"
 	"github.com/stretchr/testify/assert"
@@ -14,22 +15,22 @@ func TestMerger(t *testing.T) {
 	var errSome = errors.New("Some")
 
 	type T struct {
-		I      int    `name:"i" default:"1"`
-		S      string `name:"s" default:"s"`
-		II     []int  `name:"ii" default:"[1]"`
+		I      int     `name:"i" default:"1"`
+		S      string  `name:"s" default:"s"`
+		II     [][]int `name:"ii" default:"[[1]]"`
 		Ignore int
 	}
 	defaultValue := T{
 		I:  1,
 		S:  "s",
-		II: []int{1},
+		II: [][]int{{1}},
 	}
 
 	callback := func(s internal.StructField, v string, fv func() reflect.Value) error {
 		if s.Name() != "II" {
 			return errSome
 		}
-		var xs []int
+		var xs [][]int
 		if err := json.Unmarshal([]byte(v), &xs); err != nil {
 			return err
 		}
@@ -37,11 +38,11 @@ func TestMerger(t *testing.T) {
 		return nil
 	}
 	eq := func(a, b any) (bool, error) {
-		xs, ok := a.([]int)
+		xs, ok := a.([][]int)
 		if !ok {
 			return false, nil
 		}
-		ys, ok := b.([]int)
+		ys, ok := b.([][]int)
 		if !ok {
 			return false, nil
 		}
@@ -49,9 +50,14 @@ func TestMerger(t *testing.T) {
 			return false, nil
 		}
 		for i, x := range xs {
-			if x != ys[i] {
+			if len(x) != len(ys[i]) {
 				return false, nil
 			}
+			for j, y := range x {
+				if y != ys[i][j] {
+					return false, nil
+				}
+			}
 		}
 		return true, nil
 	}
@@ -60,6 +66,7 @@ func TestMerger(t *testing.T) {
 		callback,
 		eq,
 		"",
+		nil,
 	)
 
 	for _, tc := range []struct {
@@ -73,17 +80,17 @@ func TestMerger(t *testing.T) {
 			left: T{
 				I:  100,
 				S:  "s", // default
-				II: []int{100},
+				II: [][]int{{100}},
 			},
 			right: T{
 				I:  1, // default
 				S:  "win",
-				II: []int{1}, // default
+				II: [][]int{{1}}, // default
 			},
 			want: T{
 				I:  100,
 				S:  "win",
-				II: []int{100},
+				II: [][]int{{100}},
 			},
 		},
 		{
@@ -91,17 +98,17 @@ func TestMerger(t *testing.T) {
 			left: T{
 				I:  100,
 				S:  "lost",
-				II: []int{100},
+				II: [][]int{{100}},
 			},
 			right: T{
 				I:  1000,
 				S:  "win",
-				II: []int{1000},
+				II: [][]int{{1000}},
 			},
 			want: T{
 				I:  1000,
 				S:  "win",
-				II: []int{1000},
+				II: [][]int{{1000}},
 			},
 		},
 		{
@@ -150,3 +157,272 @@ func TestMerger(t *testing.T) {
 		})
 	}
 }
+
+func TestMergerMergeFunc(t *testing.T) {
+	type T struct {
+		L      int `name:"l" default:"1"`
+		R      int `name:"r" default:"1"`
+		D      int `name:"d" default:"1"`
+		Ignore int
+	}
+
+	m := internal.NewMerger[T](nil, nil, "", nil)
+	decisions := map[string]internal.MergeDecision{}
+	got, err := m.MergeFunc(
+		T{L: 10, R: 10, D: 1, Ignore: 10},
+		T{L: 1, R: 20, D: 1, Ignore: 20},
+		func(s internal.StructField, d internal.MergeDecision) {
+			decisions[s.Name()] = d
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, T{L: 10, R: 20, D: 1}, got)
+	assert.Equal(t, map[string]internal.MergeDecision{
+		"L": internal.MergeLeft,
+		"R": internal.MergeRight,
+		"D": internal.MergeDefault,
+	}, decisions)
+}
+
+func TestMergerMergeSetFunc(t *testing.T) {
+	type T struct {
+		Replicas int    `name:"replicas" default:"3"`
+		Level    string `name:"level" default:"info"`
+		Host     string `name:"host" default:"localhost"`
+	}
+
+	m := internal.NewMerger[T](nil, nil, "", nil)
+	decisions := map[string]internal.MergeDecision{}
+	got, err := m.MergeSetFunc(
+		T{Replicas: 5, Level: "debug", Host: "localhost"},
+		T{Replicas: 3, Level: "info", Host: "example.com"},
+		func(s internal.StructField) bool {
+			return s.Name() == "Replicas"
+		},
+		func(s internal.StructField, d internal.MergeDecision) {
+			decisions[s.Name()] = d
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, T{Replicas: 3, Level: "debug", Host: "example.com"}, got)
+	assert.Equal(t, map[string]internal.MergeDecision{
+		"Replicas": internal.MergeRight,
+		"Level":    internal.MergeLeft,
+		"Host":     internal.MergeRight,
+	}, decisions)
+}
+
+func TestMergerEmbedded(t *testing.T) {
+	type Log struct {
+		Level string `name:"log_level" default:"info"`
+	}
+	type T struct {
+		Log
+		I int `name:"i" default:"1"`
+	}
+
+	m := internal.NewMerger[T](nil, nil, "", nil)
+	got, err := m.Merge(
+		T{
+			Log: Log{Level: "debug"},
+			I:   10,
+		},
+		T{
+			Log: Log{Level: "info"},
+			I:   20,
+		},
+	)
+	assert.Nil(t, err)
+	assert.Equal(t, T{
+		Log: Log{Level: "debug"},
+		I:   20,
+	}, got)
+}
+
+func TestMergerPointer(t *testing.T) {
+	type T struct {
+		I *int `name:"i"`
+		D *int `name:"d" default:"1"`
+	}
+
+	m := internal.NewMerger[T](nil, nil, "", nil)
+	for _, tc := range []struct {
+		title string
+		left  T
+		right T
+		want  T
+	}{
+		{
+			title: "all nil",
+			want: T{
+				D: new(1),
+			},
+		},
+		{
+			title: "right zero wins",
+			left: T{
+				I: new(10),
+			},
+			right: T{
+				I: new(0),
+			},
+			want: T{
+				I: new(0),
+				D: new(1),
+			},
+		},
+		{
+			title: "left",
+			left: T{
+				I: new(10),
+				D: new(10),
+			},
+			right: T{
+				D: new(1),
+			},
+			want: T{
+				I: new(10),
+				D: new(10),
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := m.Merge(tc.left, tc.right)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMergerLocation(t *testing.T) {
+	type T struct {
+		Z *time.Location `name:"z"`
+	}
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	m := internal.NewMerger[T](nil, nil, "", nil)
+	for _, tc := range []struct {
+		title string
+		left  T
+		right T
+		want  T
+	}{
+		{
+			title: "right utc wins",
+			left:  T{Z: tokyo},
+			right: T{Z: time.UTC},
+			want:  T{Z: time.UTC},
+		},
+		{
+			title: "right nil",
+			left:  T{Z: tokyo},
+			want:  T{Z: tokyo},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := m.Merge(tc.left, tc.right)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMergerSlice(t *testing.T) {
+	type T struct {
+		S []string `name:"s" default:"a,b"`
+		N []int    `name:"n"`
+	}
+
+	m := internal.NewMerger[T](nil, nil, "", nil)
+	for _, tc := range []struct {
+		title string
+		left  T
+		right T
+		want  T
+	}{
+		{
+			title: "right wins",
+			left: T{
+				S: []string{"l"},
+				N: []int{1},
+			},
+			right: T{
+				S: []string{"r"},
+				N: []int{2},
+			},
+			want: T{
+				S: []string{"r"},
+				N: []int{2},
+			},
+		},
+		{
+			title: "empty equals nil",
+			left: T{
+				S: []string{"a", "b"},
+				N: []int{1},
+			},
+			right: T{
+				S: []string{"a", "b"},
+				N: []int{},
+			},
+			want: T{
+				S: []string{"a", "b"},
+				N: []int{1},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := m.Merge(tc.left, tc.right)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestMergerMap(t *testing.T) {
+	type T struct {
+		M map[string]int `name:"m" default:"a=1"`
+	}
+
+	m := internal.NewMerger[T](nil, nil, "", nil)
+	for _, tc := range []struct {
+		title string
+		left  T
+		right T
+		want  T
+	}{
+		{
+			title: "right wins",
+			left: T{
+				M: map[string]int{"a": 2},
+			},
+			right: T{
+				M: map[string]int{"a": 1, "b": 2},
+			},
+			want: T{
+				M: map[string]int{"a": 1, "b": 2},
+			},
+		},
+		{
+			title: "left",
+			left: T{
+				M: map[string]int{"a": 2},
+			},
+			right: T{
+				M: map[string]int{"a": 1},
+			},
+			want: T{
+				M: map[string]int{"a": 2},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := m.Merge(tc.left, tc.right)
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
@@ -13,6 +13,7 @@ var (
 //   - callback: accept the converted value
 //
 // get and conv can return [ErrParseAsDefault] or [ErrSkipParse].
+// The errors of conv and callback are redacted by [RedactError].
 func NewPairSynth[T any](
 	get func(StructField) (string, error),
 	conv func(string) (T, error),
@@ -23,7 +24,8 @@ func NewPairSynth[T any](
 			x, err := get(s)
 			switch {
 			case err == nil:
-				return conv(x)
+				t, err := conv(x)
+				return t, RedactError(s, x, err)
 			case errors.Is(err, ErrParseAsDefault):
 				var t T
 				return t, nil
@@ -32,7 +34,14 @@ func NewPairSynth[T any](
 				return t, err
 			}
 		},
-		callback: callback,
+		callback: func(s StructField, v T) error {
+			err := callback(s, v)
+			if x, ok := any(v).(string); ok {
+				// the raw value of Any
+				return RedactError(s, x, err)
+			}
+			return err
+		},
 	}
 }
 
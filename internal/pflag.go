@@ -1,37 +1,191 @@
 package internal
 
 import (
+	"fmt"
 	"reflect"
+	"strings"
+	"time"
 
 	"github.com/spf13/pflag"
 )
 
 // PFlagSetReceptor returns a [Receptor] that can define the command-line flags.
-func PFlagSetReceptor(fs *pflag.FlagSet) *PairsReceptor {
-	return FlagSetReceptor(PFlagSetTypeReceptor(fs))
+// The flag name is [Tag.Flag].
+//
+// The fields of the types in registry are defined as string flags, registry can be nil.
+// The default tag values are expanded by expand if expand is not nil, see [Expander].
+func PFlagSetReceptor(fs *pflag.FlagSet, registry *Registry, expand func(string) (string, error)) *PairsReceptor {
+	return FlagSetReceptor(PFlagSetTypeReceptor(fs, registry), expand)
 }
 
 // PFlagGetReceptor returns a [Receptor] that can retrieve values from the parsed command-line flags.
+// The flag name is [Tag.Flag], the field without the flag is skipped.
 func PFlagGetReceptor(
 	ptr any,
 	fs *pflag.FlagSet,
 	anyCallback func(StructField, string, func() reflect.Value) error,
+	registry *Registry,
 ) (*PairsReceptor, error) {
-	typedReceptor, err := SetTypedReceptor(ptr, anyCallback)
+	typedReceptor, err := SetTypedReceptor(ptr, anyCallback, registry)
 	if err != nil {
 		return nil, err
 	}
 	get := func(s StructField) (string, error) {
-		if name, ok := s.Tag().Name(); ok {
-			return name, nil
+		if _, ok := s.Tag().Name(); !ok {
+			return "", ErrParseAsDefault
 		}
-		return "", ErrParseAsDefault
+		name, ok := s.Tag().Flag()
+		if !ok {
+			// no flag
+			return "", ErrSkipParse
+		}
+		if IsOptional(s.FieldType()) {
+			// leave the pointer nil when neither the flag nor the default value is given
+			_, hasDefault := s.Tag().Default()
+			if f := fs.Lookup(name); f != nil && !f.Changed && !hasDefault {
+				return "", ErrSkipParse
+			}
+		}
+		return name, nil
 	}
-	return PairsSynthReceptor(
+	r := PairsSynthReceptor(
 		get,
 		PFlagGetConverter(fs),
 		typedReceptor,
-	), nil
+	)
+	v := reflect.ValueOf(ptr)
+	r.AnyPair = NewPairSynth(
+		get,
+		func(name string) (string, error) { return name, nil },
+		func(s StructField, name string) error {
+			if name == "" {
+				// no name tag
+				return typedReceptor.Any(s, "")
+			}
+			if _, ok := registry.Lookup(s.FieldType()); ok {
+				x, err := fs.GetString(name)
+				if err != nil {
+					return err
+				}
+				return typedReceptor.Any(s, x)
+			}
+			if IsTimeType(s.FieldType()) {
+				return pflagGetTime(fs, name, v.Elem().FieldByIndex(s.Index()))
+			}
+			if IsTextType(s.FieldType()) {
+				return pflagGetText(fs, name, v.Elem().FieldByIndex(s.Index()))
+			}
+			if IsSupportedSlice(s.FieldType()) {
+				xs, err := pflagGetSlice(fs, name)
+				if err != nil {
+					return err
+				}
+				return SetSlice(v.Elem().FieldByIndex(s.Index()), xs)
+			}
+			if IsSupportedMap(s.FieldType()) {
+				m, err := pflagGetMap(fs, name)
+				if err != nil {
+					return err
+				}
+				return SetMap(v.Elem().FieldByIndex(s.Index()), m)
+			}
+			x, err := fs.GetString(name)
+			if err != nil {
+				return err
+			}
+			return typedReceptor.Any(s, x)
+		},
+	)
+	return r, nil
+}
+
+func pflagGetTime(fs *pflag.FlagSet, name string, v reflect.Value) error {
+	switch indirectType(v.Type()) {
+	case durationType:
+		x, err := fs.GetDuration(name)
+		if err != nil {
+			return err
+		}
+		allocElem(v).SetInt(int64(x))
+	case timeType:
+		x, err := fs.GetTime(name)
+		if err != nil {
+			return err
+		}
+		allocElem(v).Set(reflect.ValueOf(x))
+	default: // location
+		x, err := fs.GetString(name)
+		if err != nil {
+			return err
+		}
+		if x == "" {
+			// leave nil when neither the flag nor the default value is given
+			return nil
+		}
+		return SetTime(v, x, DefaultLayout)
+	}
+	return nil
+}
+
+func pflagGetText(fs *pflag.FlagSet, name string, v reflect.Value) error {
+	f := fs.Lookup(name)
+	if f == nil {
+		return Errorf("flag accessed but not defined: %s", name)
+	}
+	x, ok := f.Value.(*textValue)
+	if !ok {
+		return Errorf("flag %s is not a text: %s", name, f.Value.Type())
+	}
+	x.setTo(v)
+	return nil
+}
+
+func pflagGetSlice(fs *pflag.FlagSet, name string) ([]string, error) {
+	f := fs.Lookup(name)
+	if f == nil {
+		return nil, Errorf("flag accessed but not defined: %s", name)
+	}
+	x, ok := f.Value.(pflag.SliceValue)
+	if !ok {
+		return nil, Errorf("flag %s is not a slice: %s", name, f.Value.Type())
+	}
+	return x.GetSlice(), nil
+}
+
+func pflagGetMap(fs *pflag.FlagSet, name string) (map[string]string, error) {
+	f := fs.Lookup(name)
+	if f == nil {
+		return nil, Errorf("flag accessed but not defined: %s", name)
+	}
+	if x, ok := f.Value.(*sepValue); ok {
+		return SplitMap(x.String(), x.sep)
+	}
+	switch f.Value.Type() {
+	case "stringToString":
+		return fs.GetStringToString(name)
+	case "stringToInt":
+		m, err := fs.GetStringToInt(name)
+		if err != nil {
+			return nil, err
+		}
+		return formatMap(m), nil
+	case "stringToInt64":
+		m, err := fs.GetStringToInt64(name)
+		if err != nil {
+			return nil, err
+		}
+		return formatMap(m), nil
+	default:
+		return nil, Errorf("flag %s is not a map: %s", name, f.Value.Type())
+	}
+}
+
+func formatMap[T any](m map[string]T) map[string]string {
+	r := make(map[string]string, len(m))
+	for k, v := range m {
+		r[k] = fmt.Sprint(v)
+	}
+	return r
 }
 
 func pflagSetFunc[T any](
@@ -39,7 +193,7 @@ func pflagSetFunc[T any](
 	g func(string, string, T, string) *T,
 ) TypedReceptorFunc[T] {
 	return func(s StructField, defaultValue T) error {
-		if name, ok := s.Tag().Name(); ok {
+		if name, ok := s.Tag().Flag(); ok {
 			if short, ok := s.Tag().Short(); ok {
 				_ = g(name, short, defaultValue, s.Tag().Usage())
 				return nil
@@ -50,7 +204,177 @@ func pflagSetFunc[T any](
 	}
 }
 
-func PFlagSetTypeReceptor(fs *pflag.FlagSet) *DefaultTypedReceptor {
+func pflagSetSliceFunc[T any](
+	f func(string, []T, string) *[]T,
+	g func(string, string, []T, string) *[]T,
+) TypedReceptorFunc[[]string] {
+	setFlag := pflagSetFunc(f, g)
+	return func(s StructField, defaultValue []string) error {
+		xs, err := ParseSlice[T](defaultValue)
+		if err != nil {
+			return err
+		}
+		return setFlag(s, xs)
+	}
+}
+
+func pflagSetMapFunc[T any](
+	f func(string, map[string]T, string) *map[string]T,
+	g func(string, string, map[string]T, string) *map[string]T,
+) TypedReceptorFunc[map[string]string] {
+	setFlag := pflagSetFunc(f, g)
+	return func(s StructField, defaultValue map[string]string) error {
+		m, err := ParseMap[T](defaultValue)
+		if err != nil {
+			return err
+		}
+		return setFlag(s, m)
+	}
+}
+
+// pflagSetAnyFunc defines a flag for the type in registry by the string flag,
+// a flag for [IsTimeType] by the flag of the type,
+// a flag for [IsTextType] by [textValue],
+// a flag for a slice of a supported kind by the slice flag of the kind,
+// a flag for a map from string to a supported kind by the map flag of the kind,
+// a flag for a slice or a map with the sep tag value other than [DefaultSep] by [sepValue],
+// otherwise by the string flag.
+func pflagSetAnyFunc(fs *pflag.FlagSet, registry *Registry) TypedReceptorFunc[string] {
+	setString := pflagSetFunc(fs.String, fs.StringP)
+	return func(s StructField, defaultValue string) error {
+		if _, ok := registry.Lookup(s.FieldType()); ok {
+			return setString(s, defaultValue)
+		}
+
+		switch {
+		case IsTimeType(s.FieldType()):
+			return pflagSetTime(fs, s, defaultValue)
+		case IsTextType(s.FieldType()):
+			return pflagSetText(fs, s, defaultValue)
+		case (IsSupportedSlice(s.FieldType()) || IsSupportedMap(s.FieldType())) && s.Tag().Sep() != DefaultSep:
+			return pflagSetSep(fs, s, defaultValue)
+		case IsSupportedSlice(s.FieldType()):
+			return pflagSetSlice(fs, s, SplitSlice(defaultValue, s.Tag().Sep()))
+		case IsSupportedMap(s.FieldType()):
+			m, err := SplitMap(defaultValue, s.Tag().Sep())
+			if err != nil {
+				return err
+			}
+			return pflagSetMap(fs, s, m)
+		default:
+			return setString(s, defaultValue)
+		}
+	}
+}
+
+func pflagSetTime(fs *pflag.FlagSet, s StructField, defaultValue string) error {
+	switch indirectType(s.FieldType()) {
+	case durationType:
+		var x time.Duration
+		if defaultValue != "" {
+			v, err := time.ParseDuration(defaultValue)
+			if err != nil {
+				return err
+			}
+			x = v
+		}
+		return pflagSetFunc(fs.Duration, fs.DurationP)(s, x)
+	case timeType:
+		var (
+			x      time.Time
+			layout = s.Tag().Layout()
+		)
+		if defaultValue != "" {
+			v, err := time.Parse(layout, defaultValue)
+			if err != nil {
+				return err
+			}
+			x = v
+		}
+		formats := []string{layout}
+		return pflagSetFunc(
+			func(name string, value time.Time, usage string) *time.Time {
+				return fs.Time(name, value, formats, usage)
+			},
+			func(name, shorthand string, value time.Time, usage string) *time.Time {
+				return fs.TimeP(name, shorthand, value, formats, usage)
+			},
+		)(s, x)
+	default: // location
+		return pflagSetFunc(fs.String, fs.StringP)(s, defaultValue)
+	}
+}
+
+func pflagSetText(fs *pflag.FlagSet, s StructField, defaultValue string) error {
+	name, ok := s.Tag().Flag()
+	if !ok {
+		return nil
+	}
+	v, err := newTextValue(s.FieldType(), defaultValue)
+	if err != nil {
+		return err
+	}
+	short, _ := s.Tag().Short()
+	fs.VarP(v, name, short, s.Tag().Usage())
+	return nil
+}
+
+func pflagSetSep(fs *pflag.FlagSet, s StructField, defaultValue string) error {
+	name, ok := s.Tag().Flag()
+	if !ok {
+		return nil
+	}
+	v, err := newSepValue(s, defaultValue)
+	if err != nil {
+		return err
+	}
+	short, _ := s.Tag().Short()
+	fs.VarP(v, name, short, s.Tag().Usage())
+	return nil
+}
+
+func pflagSetSlice(fs *pflag.FlagSet, s StructField, xs []string) error {
+	if s.FieldType().Elem() == durationType {
+		return pflagSetSliceFunc(fs.DurationSlice, fs.DurationSliceP)(s, xs)
+	}
+	switch s.FieldType().Elem().Kind() {
+	case reflect.Bool:
+		return pflagSetSliceFunc(fs.BoolSlice, fs.BoolSliceP)(s, xs)
+	case reflect.Int:
+		return pflagSetSliceFunc(fs.IntSlice, fs.IntSliceP)(s, xs)
+	case reflect.Int32:
+		return pflagSetSliceFunc(fs.Int32Slice, fs.Int32SliceP)(s, xs)
+	case reflect.Int64:
+		return pflagSetSliceFunc(fs.Int64Slice, fs.Int64SliceP)(s, xs)
+	case reflect.Uint:
+		return pflagSetSliceFunc(fs.UintSlice, fs.UintSliceP)(s, xs)
+	case reflect.Float32:
+		return pflagSetSliceFunc(fs.Float32Slice, fs.Float32SliceP)(s, xs)
+	case reflect.Float64:
+		return pflagSetSliceFunc(fs.Float64Slice, fs.Float64SliceP)(s, xs)
+	default:
+		// the elements are converted when retrieved
+		return pflagSetSliceFunc(fs.StringSlice, fs.StringSliceP)(s, xs)
+	}
+}
+
+func pflagSetMap(fs *pflag.FlagSet, s StructField, m map[string]string) error {
+	if s.FieldType().Elem() == durationType {
+		// the values are converted when retrieved
+		return pflagSetMapFunc(fs.StringToString, fs.StringToStringP)(s, m)
+	}
+	switch s.FieldType().Elem().Kind() {
+	case reflect.Int:
+		return pflagSetMapFunc(fs.StringToInt, fs.StringToIntP)(s, m)
+	case reflect.Int64:
+		return pflagSetMapFunc(fs.StringToInt64, fs.StringToInt64P)(s, m)
+	default:
+		// the values are converted when retrieved
+		return pflagSetMapFunc(fs.StringToString, fs.StringToStringP)(s, m)
+	}
+}
+
+func PFlagSetTypeReceptor(fs *pflag.FlagSet, registry *Registry) *DefaultTypedReceptor {
 	return &DefaultTypedReceptor{
 		BoolFunc:    pflagSetFunc(fs.Bool, fs.BoolP),
 		IntFunc:     pflagSetFunc(fs.Int, fs.IntP),
@@ -66,7 +390,7 @@ func PFlagSetTypeReceptor(fs *pflag.FlagSet) *DefaultTypedReceptor {
 		Float32Func: pflagSetFunc(fs.Float32, fs.Float32P),
 		Float64Func: pflagSetFunc(fs.Float64, fs.Float64P),
 		StringFunc:  pflagSetFunc(fs.String, fs.StringP),
-		AnyFunc:     pflagSetFunc(fs.String, fs.StringP),
+		AnyFunc:     pflagSetAnyFunc(fs, registry),
 	}
 }
 
@@ -88,3 +412,70 @@ func PFlagGetConverter(fs *pflag.FlagSet) *DefaultConverter {
 		StringFunc:  fs.GetString,
 	}
 }
+
+var (
+	_ pflag.Value      = &sepValue{}
+	_ pflag.SliceValue = &sepValue{}
+)
+
+// sepValue is a [pflag.Value] of a slice or a map split by the sep tag value, see [Tag.Sep].
+// The value given more than once is appended.
+// The elements are converted when retrieved.
+type sepValue struct {
+	typ     reflect.Type
+	sep     string
+	values  []string
+	changed bool
+}
+
+func newSepValue(s StructField, defaultValue string) (*sepValue, error) {
+	v := &sepValue{
+		typ:    s.FieldType(),
+		sep:    s.Tag().Sep(),
+		values: SplitSlice(defaultValue, s.Tag().Sep()),
+	}
+	if err := v.validate(v.values); err != nil {
+		return nil, err
+	}
+	return v, nil
+}
+
+// validate reports an error if xs cannot be converted into the elements.
+func (v *sepValue) validate(xs []string) error {
+	x := reflect.New(v.typ).Elem()
+	if v.typ.Kind() == reflect.Map {
+		m, err := SplitMap(strings.Join(xs, v.sep), v.sep)
+		if err != nil {
+			return err
+		}
+		return SetMap(x, m)
+	}
+	return SetSlice(x, xs)
+}
+
+func (v *sepValue) Set(s string) error {
+	xs := SplitSlice(s, v.sep)
+	if err := v.validate(xs); err != nil {
+		return err
+	}
+	if !v.changed {
+		v.values = xs
+		v.changed = true
+		return nil
+	}
+	v.values = append(v.values, xs...)
+	return nil
+}
+
+func (v *sepValue) String() string        { return strings.Join(v.values, v.sep) }
+func (v *sepValue) Type() string          { return v.typ.String() }
+func (v *sepValue) GetSlice() []string    { return v.values }
+func (v *sepValue) Append(s string) error { return v.Replace(append(v.values, s)) }
+
+func (v *sepValue) Replace(xs []string) error {
+	if err := v.validate(xs); err != nil {
+		return err
+	}
+	v.values = xs
+	return nil
+}
@@ -5,6 +5,7 @@ import (
 	"reflect"
 	"sort"
 	"testing"
+	"time"
 
 	"github.com/berquerant/structconfig/internal"
 	"github.com/spf13/pflag"
@@ -19,7 +20,8 @@ func TestPFlagReceptor(t *testing.T) {
 		F         float32 `name:"ff" default:"1.1" usage:"FLOAT"`
 		S         string  `name:"fs" default:"str" usage:"STRING" short:"s"`
 		NoDefault int     `name:"fnodefault"`
-		Slice     []int   `name:"fslice" default:"[1,2]"`
+		Slice     []int   `name:"fslice" default:"1,2"`
+		Any       [][]int `name:"fany" default:"[[1]]"`
 		Ignore1   int
 		Ignore2   int `name:"-" default:"1000" usage:"IGNORE2"`
 	}
@@ -32,6 +34,7 @@ func TestPFlagReceptor(t *testing.T) {
 		"fs",
 		"fnodefault",
 		"fslice",
+		"fany",
 	}
 	sort.Strings(flagNames)
 
@@ -55,6 +58,7 @@ func TestPFlagReceptor(t *testing.T) {
 				F:     1.1,
 				S:     "SHORT",
 				Slice: []int{1, 2},
+				Any:   [][]int{{1}},
 			},
 		},
 		{
@@ -66,7 +70,8 @@ func TestPFlagReceptor(t *testing.T) {
 				"--ff", "10.1",
 				"--fs", "changed",
 				"--fnodefault", "-24",
-				"--fslice", "[]",
+				"--fslice", "3",
+				"--fany", "[]",
 			},
 			want: T{
 				B:         true,
@@ -75,18 +80,20 @@ func TestPFlagReceptor(t *testing.T) {
 				F:         10.1,
 				S:         "changed",
 				NoDefault: -24,
-				Slice:     []int{},
+				Slice:     []int{3},
+				Any:       [][]int{},
 			},
 		},
 		{
 			title: "change fslice",
-			args:  []string{"--fslice", "[1,2,3]"},
+			args:  []string{"--fslice", "1,2", "--fslice", "3"},
 			want: T{
 				I:     1,
 				U:     10,
 				F:     1.1,
 				S:     "str",
 				Slice: []int{1, 2, 3},
+				Any:   [][]int{{1}},
 			},
 		},
 		{
@@ -98,6 +105,7 @@ func TestPFlagReceptor(t *testing.T) {
 				F:     1.1,
 				S:     "str",
 				Slice: []int{1, 2},
+				Any:   [][]int{{1}},
 			},
 		},
 		{
@@ -108,6 +116,7 @@ func TestPFlagReceptor(t *testing.T) {
 				F:     1.1,
 				S:     "str",
 				Slice: []int{1, 2},
+				Any:   [][]int{{1}},
 			},
 		},
 	} {
@@ -115,7 +124,7 @@ func TestPFlagReceptor(t *testing.T) {
 			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
 
 			t.Run("Set", func(t *testing.T) {
-				r := internal.PFlagSetReceptor(fs)
+				r := internal.PFlagSetReceptor(fs, nil, nil)
 				assert.Nil(t, typ.Accept(r))
 			})
 
@@ -138,13 +147,14 @@ func TestPFlagReceptor(t *testing.T) {
 					&got,
 					fs,
 					func(_ internal.StructField, v string, fv func() reflect.Value) error {
-						var xs []int
+						var xs [][]int
 						if err := json.Unmarshal([]byte(v), &xs); err != nil {
 							return err
 						}
 						fv().Set(reflect.ValueOf(xs))
 						return nil
 					},
+					nil,
 				)
 				assert.Nil(t, err)
 
@@ -155,3 +165,215 @@ func TestPFlagReceptor(t *testing.T) {
 	}
 
 }
+
+func TestPFlagReceptorPointer(t *testing.T) {
+	type T struct {
+		I *int    `name:"fi"`
+		S *string `name:"fs" default:"str"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	for _, tc := range []struct {
+		title string
+		args  []string
+		i     *int
+		s     string
+	}{
+		{
+			title: "default",
+			s:     "str",
+		},
+		{
+			title: "zero",
+			args:  []string{"--fi", "0"},
+			i:     new(0),
+			s:     "str",
+		},
+		{
+			title: "change all",
+			args:  []string{"--fi", "1", "--fs", "changed"},
+			i:     new(1),
+			s:     "changed",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, nil, nil)))
+			assert.Nil(t, fs.Parse(tc.args))
+
+			var got T
+			r, err := internal.PFlagGetReceptor(&got, fs, nil, nil)
+			assert.Nil(t, err)
+			assert.Nil(t, typ.Accept(r))
+
+			assert.Equal(t, tc.i, got.I)
+			if assert.NotNil(t, got.S) {
+				assert.Equal(t, tc.s, *got.S)
+			}
+		})
+	}
+}
+
+func TestPFlagReceptorMap(t *testing.T) {
+	type T struct {
+		S map[string]string  `name:"fs" default:"a=x"`
+		I map[string]int     `name:"fi"`
+		U map[string]uint    `name:"fu" default:"a=1"`
+		B map[string]float64 `name:"fb"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	for _, tc := range []struct {
+		title string
+		args  []string
+		want  T
+	}{
+		{
+			title: "default",
+			want: T{
+				S: map[string]string{"a": "x"},
+				I: map[string]int{},
+				U: map[string]uint{"a": 1},
+				B: map[string]float64{},
+			},
+		},
+		{
+			title: "change all",
+			args: []string{
+				"--fs", "b=y,c=z",
+				"--fi", "a=1", "--fi", "b=2",
+				"--fu", "b=2",
+				"--fb", "a=1.5",
+			},
+			want: T{
+				S: map[string]string{"b": "y", "c": "z"},
+				I: map[string]int{"a": 1, "b": 2},
+				U: map[string]uint{"b": 2},
+				B: map[string]float64{"a": 1.5},
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, nil, nil)))
+			assert.Nil(t, fs.Parse(tc.args))
+
+			var got T
+			r, err := internal.PFlagGetReceptor(&got, fs, nil, nil)
+			assert.Nil(t, err)
+			assert.Nil(t, typ.Accept(r))
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPFlagReceptorSep(t *testing.T) {
+	type T struct {
+		Ports  []int          `name:"ports" sep:";" default:"80;443"`
+		Hosts  []string       `name:"hosts" sep:";"`
+		Labels map[string]int `name:"labels" sep:";" default:"a=1"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	for _, tc := range []struct {
+		title string
+		args  []string
+		want  T
+		err   bool
+	}{
+		{
+			title: "default",
+			want: T{
+				Ports:  []int{80, 443},
+				Hosts:  []string{},
+				Labels: map[string]int{"a": 1},
+			},
+		},
+		{
+			title: "change all",
+			args: []string{
+				"--ports", "1;2", "--ports", "3",
+				"--hosts", "a,b;c",
+				"--labels", "b=2;c=3",
+			},
+			want: T{
+				Ports:  []int{1, 2, 3},
+				Hosts:  []string{"a,b", "c"},
+				Labels: map[string]int{"b": 2, "c": 3},
+			},
+		},
+		{
+			title: "invalid element",
+			args:  []string{"--ports", "1,2"},
+			err:   true,
+		},
+		{
+			title: "invalid map element",
+			args:  []string{"--labels", "a"},
+			err:   true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+			assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, nil, nil)))
+			err := fs.Parse(tc.args)
+			if tc.err {
+				assert.NotNil(t, err)
+				return
+			}
+			assert.Nil(t, err)
+
+			var got T
+			r, err := internal.PFlagGetReceptor(&got, fs, nil, nil)
+			assert.Nil(t, err)
+			assert.Nil(t, typ.Accept(r))
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestPFlagReceptorFlagTag(t *testing.T) {
+	type DB struct {
+		Host  string        `name:"host" flag:"db-host" default:"localhost"`
+		Port  int           `name:"port" default:"5432"`
+		Hosts []string      `name:"hosts" flag:"db-hosts"`
+		Wait  time.Duration `name:"wait" flag:"db-wait"`
+		Skip  int           `name:"skip" flag:"-" default:"1"`
+	}
+	type T struct {
+		DB DB `name:"db"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+	assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, nil, nil)))
+	assert.Nil(t, fs.Lookup("db.host"))
+	assert.Nil(t, fs.Lookup("db.skip"))
+	assert.Nil(t, fs.Parse([]string{
+		"--db-host", "example.com",
+		"--db.port", "15432",
+		"--db-hosts", "a,b",
+		"--db-wait", "1s",
+	}))
+
+	var got T
+	r, err := internal.PFlagGetReceptor(&got, fs, nil, nil)
+	assert.Nil(t, err)
+	assert.Nil(t, typ.Accept(r))
+	assert.Equal(t, T{
+		DB: DB{
+			Host:  "example.com",
+			Port:  15432,
+			Hosts: []string{"a", "b"},
+			Wait:  time.Second,
+		},
+	}, got)
+}
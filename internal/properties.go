@@ -0,0 +1,158 @@
+package internal
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DecodeProperties reads a Java .properties file into [Document].
+// The values are wrapped by [*DocumentNode] to hold the line and the column.
+//
+// The dotted keys like "db.host" are kept as is, they are found by [Document.Lookup].
+// The key is separated from the value by '=', ':' or whitespaces.
+// Lines starting with '#' or '!' are comments.
+// A line ending with an odd number of backslashes continues to the next line.
+// Escape sequences like \t, \n and \uXXXX are unescaped.
+// A later key overrides the same key.
+func DecodeProperties(r io.Reader) (Document, error) {
+	doc := Document{}
+	scanner := bufio.NewScanner(r)
+	lineNumber := 0
+	nextLine := func() (string, bool) {
+		if !scanner.Scan() {
+			return "", false
+		}
+		lineNumber++
+		line := strings.TrimSuffix(scanner.Text(), "\r")
+		if lineNumber == 1 {
+			line = strings.TrimPrefix(line, "\ufeff")
+		}
+		return line, true
+	}
+
+	for {
+		line, ok := nextLine()
+		if !ok {
+			break
+		}
+		trimmed := strings.TrimLeft(line, " \t\f")
+		if trimmed == "" || trimmed[0] == '#' || trimmed[0] == '!' {
+			continue
+		}
+		start := lineNumber
+		indent := utf8.RuneCountInString(line[:len(line)-len(trimmed)])
+
+		// join the continuation lines
+		logical := trimmed
+		for isPropertiesContinued(logical) {
+			logical = logical[:len(logical)-1]
+			next, ok := nextLine()
+			if !ok {
+				break
+			}
+			logical += strings.TrimLeft(next, " \t\f")
+		}
+
+		key, valueOffset, value, err := splitPropertiesLine(logical)
+		if err != nil {
+			return nil, Errorf("decode properties: line %d: %v", start, err)
+		}
+		doc[key] = &DocumentNode{
+			Value:  value,
+			Line:   start,
+			Column: indent + utf8.RuneCountInString(logical[:min(valueOffset, len(logical))]) + 1,
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, JoinErrors(Errorf("decode properties"), err)
+	}
+	return doc, nil
+}
+
+// isPropertiesContinued reports true if line ends with an odd number of backslashes.
+func isPropertiesContinued(line string) bool {
+	n := len(line) - len(strings.TrimRight(line, `\`))
+	return n%2 == 1
+}
+
+// splitPropertiesLine splits line into the key and the value.
+// valueOffset is the byte offset of the value in line.
+func splitPropertiesLine(line string) (key string, valueOffset int, value string, err error) {
+	i := 0
+	for i < len(line) {
+		c := line[i]
+		if c == '\\' {
+			i += 2
+			continue
+		}
+		if c == '=' || c == ':' || c == ' ' || c == '\t' || c == '\f' {
+			break
+		}
+		i++
+	}
+	i = min(i, len(line))
+	if key, err = unescapeProperties(line[:i]); err != nil {
+		return
+	}
+
+	// skip whitespaces, a separator and whitespaces
+	j := i
+	for j < len(line) && strings.IndexByte(" \t\f", line[j]) >= 0 {
+		j++
+	}
+	if j < len(line) && (line[j] == '=' || line[j] == ':') {
+		j++
+		for j < len(line) && strings.IndexByte(" \t\f", line[j]) >= 0 {
+			j++
+		}
+	}
+	valueOffset = j
+	value, err = unescapeProperties(line[j:])
+	return
+}
+
+func unescapeProperties(s string) (string, error) {
+	if !strings.Contains(s, `\`) {
+		return s, nil
+	}
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c != '\\' {
+			b.WriteByte(c)
+			continue
+		}
+		i++
+		if i >= len(s) {
+			break
+		}
+		switch e := s[i]; e {
+		case 't':
+			b.WriteByte('\t')
+		case 'n':
+			b.WriteByte('\n')
+		case 'r':
+			b.WriteByte('\r')
+		case 'f':
+			b.WriteByte('\f')
+		case 'u':
+			if i+5 > len(s) {
+				return "", errors.New(`invalid \u escape`)
+			}
+			x, err := strconv.ParseUint(s[i+1:i+5], 16, 16)
+			if err != nil {
+				return "", fmt.Errorf(`invalid \u escape %s`, s[i+1:i+5])
+			}
+			b.WriteRune(rune(x))
+			i += 4
+		default:
+			b.WriteByte(e)
+		}
+	}
+	return b.String(), nil
+}
@@ -0,0 +1,146 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeProperties(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		input string
+		want  map[string]any
+	}{
+		{
+			title: "empty",
+			input: "",
+			want:  map[string]any{},
+		},
+		{
+			title: "separators",
+			input: `# comment
+! comment
+a=1
+b : 2
+c 3
+  d=4
+e
+f=
+`,
+			want: map[string]any{
+				"a": "1",
+				"b": "2",
+				"c": "3",
+				"d": "4",
+				"e": "",
+				"f": "",
+			},
+		},
+		{
+			title: "dotted keys",
+			input: `db.host=localhost
+db.port=5432
+`,
+			want: map[string]any{
+				"db.host": "localhost",
+				"db.port": "5432",
+			},
+		},
+		{
+			title: "escapes",
+			input: `key\ with\=sep = a\tb\u00e9\\
+path=C:\\dir
+`,
+			want: map[string]any{
+				"key with=sep": "a\tbé\\",
+				"path":         `C:\dir`,
+			},
+		},
+		{
+			title: "continuation",
+			input: `hosts = a,\
+        b,\
+        c
+next = 1
+`,
+			want: map[string]any{
+				"hosts": "a,b,c",
+				"next":  "1",
+			},
+		},
+		{
+			title: "override",
+			input: "a=1\na=2\n",
+			want: map[string]any{
+				"a": "2",
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := internal.DecodeProperties(strings.NewReader(tc.input))
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, plainDocument(got))
+		})
+	}
+}
+
+func TestDecodePropertiesError(t *testing.T) {
+	_, err := internal.DecodeProperties(strings.NewReader("a=1\nb=\\u00x1\n"))
+	assert.ErrorIs(t, err, internal.ErrStructConfig)
+	assert.ErrorContains(t, err, `line 2: invalid \u escape 00x1`)
+}
+
+func TestPropertiesDocumentReceptor(t *testing.T) {
+	type DB struct {
+		Host string `name:"host"`
+		Port int    `name:"port" default:"5432"`
+	}
+	type T struct {
+		Hosts []string `name:"hosts"`
+		DB    DB       `name:"db"`
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		const input = `hosts=a,\
+  b
+db.host=localhost
+`
+		doc, err := internal.DecodeProperties(strings.NewReader(input))
+		assert.Nil(t, err)
+
+		var got T
+		r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+		assert.Nil(t, err)
+
+		typ, err := internal.NewType(got, "")
+		assert.Nil(t, err)
+
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			Hosts: []string{"a", "b"},
+			DB: DB{
+				Host: "localhost",
+				Port: 5432,
+			},
+		}, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		doc, err := internal.DecodeProperties(strings.NewReader("db.host=localhost\n  db.port = x\n"))
+		assert.Nil(t, err)
+
+		var got T
+		r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+		assert.Nil(t, err)
+
+		typ, err := internal.NewType(got, "")
+		assert.Nil(t, err)
+
+		err = typ.Accept(r)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorContains(t, err, "line 2, column 13: DB.Port (db.port): ")
+	})
+}
@@ -0,0 +1,202 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// DefaultRefPrefix starts a reference to another field in the default tag value, e.g. ${.listen_host}.
+// The name after it is the name tag value of the field.
+const DefaultRefPrefix = "${."
+
+// HasDefaultRef reports true if v, the default tag value, references other fields.
+func HasDefaultRef(v string) bool {
+	found := false
+	_, _ = scanDefaultRefs(v, func(string) (string, error) {
+		found = true
+		return "", nil
+	})
+	return found
+}
+
+// scanDefaultRefs replaces the references ${.name} in v by f.
+// $$ is left as is and not a part of references.
+func scanDefaultRefs(v string, f func(name string) (string, error)) (string, error) {
+	var b strings.Builder
+	for i := 0; i < len(v); {
+		switch {
+		case strings.HasPrefix(v[i:], "$$"):
+			b.WriteString("$$")
+			i += 2
+		case strings.HasPrefix(v[i:], DefaultRefPrefix):
+			end := strings.IndexByte(v[i:], '}')
+			if end < 0 {
+				// not a reference
+				b.WriteString(v[i:])
+				return b.String(), nil
+			}
+			name := v[i+len(DefaultRefPrefix) : i+end]
+			x, err := f(name)
+			if err != nil {
+				return "", err
+			}
+			b.WriteString(x)
+			i += end + 1
+		default:
+			b.WriteByte(v[i])
+			i++
+		}
+	}
+	return b.String(), nil
+}
+
+// defaultRefs returns the names referenced by v.
+func defaultRefs(v string) []string {
+	names := []string{}
+	_, _ = scanDefaultRefs(v, func(name string) (string, error) {
+		names = append(names, name)
+		return "", nil
+	})
+	return names
+}
+
+// ResolveDefaultRefs sets the default tag values that reference other fields, like ${.listen_host}:9090,
+// to the fields of ptr that are not set, and returns the set values by the Go field names.
+//
+// isSet reports true if some source set the field.
+// If isSet is nil, the fields that are zero or empty are not set.
+//
+// The references are replaced by the values of the referenced fields formatted by [FormatValue],
+// in dependency order; the referenced fields with references are resolved first.
+// The result is expanded by expand if expand is not nil, see [Expander].
+// Unknown names and cycles of references are errors even if the fields are not zero.
+//
+// ptr should be a pointer of struct.
+func ResolveDefaultRefs(
+	ptr any,
+	typ *Type,
+	anyCallback func(StructField, string, func() reflect.Value) error,
+	registry *Registry,
+	expand func(string) (string, error),
+	isSet func(StructField) bool,
+) (map[string]string, error) {
+	rv := reflect.ValueOf(ptr)
+	if rv.Kind() != reflect.Pointer || rv.Elem().Kind() != reflect.Struct {
+		return nil, JoinErrors(ErrNotStructPointer)
+	}
+
+	var (
+		byName = map[string]StructField{}
+		refs   = map[string][]string{} // name -> referenced names
+		names  = []string{}            // names with references in field order
+	)
+	for _, f := range typ.Fields() {
+		name, ok := f.Tag().Name()
+		if !ok {
+			continue
+		}
+		byName[name] = f
+		if d, ok := f.Tag().Default(); ok && HasDefaultRef(d) {
+			refs[name] = defaultRefs(d)
+			names = append(names, name)
+		}
+	}
+
+	order, err := sortDefaultRefs(names, refs, byName)
+	if err != nil {
+		return nil, err
+	}
+
+	resolved := map[string]string{}
+	for _, name := range order {
+		f := byName[name]
+		if isSet != nil {
+			if isSet(f) {
+				continue
+			}
+		} else if !isMissing(rv.Elem().FieldByIndex(f.Index())) {
+			continue
+		}
+		d, _ := f.Tag().Default()
+		v, err := scanDefaultRefs(d, func(ref string) (string, error) {
+			x, err := FormatValue(byName[ref], rv.Elem().FieldByIndex(byName[ref].Index()))
+			if err != nil {
+				return "", err
+			}
+			if expand != nil {
+				x = ExpandEscape(x)
+			}
+			return x, nil
+		})
+		if err != nil {
+			return nil, Errorf("%s: %v", f.Name(), err)
+		}
+		if v, err = expandValue(f, v, expand); err != nil {
+			return nil, err
+		}
+
+		r, err := SetReceptor(ptr, func(s StructField) (string, error) {
+			if s.Name() != f.Name() {
+				return "", ErrSkipParse
+			}
+			return v, nil
+		}, NewConv(), anyCallback, registry)
+		if err != nil {
+			return nil, err
+		}
+		if err := typ.Accept(r); err != nil {
+			return nil, err
+		}
+		resolved[f.Name()] = v
+	}
+	return resolved, nil
+}
+
+// sortDefaultRefs sorts names so that the referenced names come first.
+func sortDefaultRefs(names []string, refs map[string][]string, byName map[string]StructField) ([]string, error) {
+	const (
+		visiting = iota + 1
+		visited
+	)
+	var (
+		state = map[string]int{}
+		order = []string{}
+		stack = []string{}
+		visit func(name string) error
+	)
+	visit = func(name string) error {
+		switch state[name] {
+		case visited:
+			return nil
+		case visiting:
+			i := 0
+			for stack[i] != name {
+				i++
+			}
+			cycle := append(append([]string{}, stack[i:]...), name)
+			return Errorf("default reference cycle: %s", strings.Join(cycle, " -> "))
+		}
+		state[name] = visiting
+		stack = append(stack, name)
+		for _, ref := range refs[name] {
+			if _, ok := byName[ref]; !ok {
+				return Errorf("%s: unknown default reference %s%s}", byName[name].Name(), DefaultRefPrefix, ref)
+			}
+			if err := visit(ref); err != nil {
+				return err
+			}
+		}
+		stack = stack[:len(stack)-1]
+		state[name] = visited
+		if _, ok := refs[name]; ok {
+			order = append(order, name)
+		}
+		return nil
+	}
+	for _, name := range names {
+		if err := visit(name); err != nil {
+			return nil, err
+		}
+	}
+	return order, nil
+}
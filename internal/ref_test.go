@@ -0,0 +1,192 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHasDefaultRef(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  bool
+	}{
+		{input: "", want: false},
+		{input: "${HOME}/x", want: false},
+		{input: "${.data_dir}/cache", want: true},
+		{input: "$${.data_dir}", want: false},
+		{input: "${.data_dir", want: false},
+	} {
+		t.Run(tc.input, func(t *testing.T) {
+			assert.Equal(t, tc.want, internal.HasDefaultRef(tc.input))
+		})
+	}
+}
+
+func TestResolveDefaultRefs(t *testing.T) {
+	type DB struct {
+		Host string `name:"host" default:"${.listen_host}"`
+	}
+	type T struct {
+		MetricsAddr string   `name:"metrics_addr" default:"${.listen_host}:${.metrics_port}"`
+		ListenHost  string   `name:"listen_host" default:"0.0.0.0"`
+		MetricsPort int      `name:"metrics_port" default:"9090"`
+		CacheDir    string   `name:"cache_dir" default:"${.data_dir}/cache"`
+		DataDir     string   `name:"data_dir" default:"${.base_dir}/data"`
+		BaseDir     string   `name:"base_dir" default:"/var"`
+		Port        int      `name:"port" default:"${.metrics_port}"`
+		Hosts       []string `name:"hosts" default:"${.listen_host},b"`
+		DB          DB       `name:"db"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	t.Run("from default", func(t *testing.T) {
+		var got T
+		r, err := internal.DefaultReceptor(&got, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			ListenHost:  "0.0.0.0",
+			MetricsPort: 9090,
+			BaseDir:     "/var",
+		}, got, "references are left zero")
+
+		resolved, err := internal.ResolveDefaultRefs(&got, typ, nil, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, T{
+			MetricsAddr: "0.0.0.0:9090",
+			ListenHost:  "0.0.0.0",
+			MetricsPort: 9090,
+			CacheDir:    "/var/data/cache",
+			DataDir:     "/var/data",
+			BaseDir:     "/var",
+			Port:        9090,
+			Hosts:       []string{"0.0.0.0", "b"},
+			DB: DB{
+				Host: "0.0.0.0",
+			},
+		}, got)
+		assert.Equal(t, map[string]string{
+			"MetricsAddr": "0.0.0.0:9090",
+			"CacheDir":    "/var/data/cache",
+			"DataDir":     "/var/data",
+			"Port":        "9090",
+			"Hosts":       "0.0.0.0,b",
+			"DB.Host":     "0.0.0.0",
+		}, resolved)
+	})
+
+	t.Run("keep set values", func(t *testing.T) {
+		got := T{
+			ListenHost:  "10.0.0.1",
+			MetricsPort: 9100,
+			DataDir:     "/data",
+			Port:        80,
+		}
+		_, err := internal.ResolveDefaultRefs(&got, typ, nil, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Equal(t, T{
+			MetricsAddr: "10.0.0.1:9100",
+			ListenHost:  "10.0.0.1",
+			MetricsPort: 9100,
+			CacheDir:    "/data/cache",
+			DataDir:     "/data",
+			Port:        80,
+			Hosts:       []string{"10.0.0.1", "b"},
+			DB: DB{
+				Host: "10.0.0.1",
+			},
+		}, got)
+	})
+
+	t.Run("is set", func(t *testing.T) {
+		got := T{
+			ListenHost:  "0.0.0.0",
+			MetricsPort: 9090,
+			BaseDir:     "/var",
+		}
+		isSet := func(f internal.StructField) bool {
+			return f.Name() == "Port" || f.Name() == "Hosts"
+		}
+		resolved, err := internal.ResolveDefaultRefs(&got, typ, nil, nil, nil, isSet)
+		assert.Nil(t, err)
+		assert.Equal(t, 0, got.Port, "explicit zero is kept")
+		assert.Nil(t, got.Hosts, "explicit empty is kept")
+		assert.Equal(t, "0.0.0.0:9090", got.MetricsAddr)
+		assert.NotContains(t, resolved, "Port")
+		assert.NotContains(t, resolved, "Hosts")
+	})
+
+	t.Run("expand", func(t *testing.T) {
+		type T struct {
+			A string `name:"a" default:"$$x"`
+			B string `name:"b" default:"${.a}:${HOME}:$${.a}"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		assert.Nil(t, err)
+
+		got := T{A: "$HOME"}
+		_, err = internal.ResolveDefaultRefs(&got, typ, nil, nil,
+			internal.Expander(internal.EnvironLookup([]string{"HOME=/home/user"})), nil)
+		assert.Nil(t, err)
+		assert.Equal(t, "$HOME:/home/user:${.a}", got.B)
+	})
+}
+
+func TestResolveDefaultRefsError(t *testing.T) {
+	t.Run("cycle", func(t *testing.T) {
+		type T struct {
+			X string `name:"x" default:"${.a}"`
+			A string `name:"a" default:"${.b}"`
+			B string `name:"b" default:"${.c}"`
+			C string `name:"c" default:"${.a}"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		assert.Nil(t, err)
+
+		got := T{A: "set"}
+		_, err = internal.ResolveDefaultRefs(&got, typ, nil, nil, nil, nil)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorContains(t, err, "default reference cycle: a -> b -> c -> a")
+	})
+
+	t.Run("self", func(t *testing.T) {
+		type T struct {
+			A string `name:"a" default:"${.a}"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		assert.Nil(t, err)
+
+		var got T
+		_, err = internal.ResolveDefaultRefs(&got, typ, nil, nil, nil, nil)
+		assert.ErrorContains(t, err, "default reference cycle: a -> a")
+	})
+
+	t.Run("unknown", func(t *testing.T) {
+		type T struct {
+			A string `name:"a" default:"${.unknown}"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		assert.Nil(t, err)
+
+		var got T
+		_, err = internal.ResolveDefaultRefs(&got, typ, nil, nil, nil, nil)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorContains(t, err, "A: unknown default reference ${.unknown}")
+	})
+
+	t.Run("parse", func(t *testing.T) {
+		type T struct {
+			A string `name:"a" default:"x"`
+			B int    `name:"b" default:"${.a}"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		assert.Nil(t, err)
+
+		got := T{A: "x"}
+		_, err = internal.ResolveDefaultRefs(&got, typ, nil, nil, nil, nil)
+		assert.NotNil(t, err)
+	})
+}
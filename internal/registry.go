@@ -0,0 +1,76 @@
+package internal
+
+import "reflect"
+
+// NewRegistry returns a new empty [Registry].
+func NewRegistry() *Registry {
+	return &Registry{
+		entries: map[reflect.Type]*RegistryEntry{},
+	}
+}
+
+// Registry is a set of conversions keyed by the type of the field.
+//
+// The conversion of the type of the field is used before the other conversions
+// for the fields passed to [AnyReceptor].
+type Registry struct {
+	entries map[reflect.Type]*RegistryEntry
+}
+
+// RegistryEntry is a conversion of a type.
+type RegistryEntry struct {
+	// Parse converts a string into a value of the type.
+	Parse func(string) (any, error)
+	// Equal reports true if left equals right, optional.
+	Equal func(left, right any) (bool, error)
+}
+
+// Register registers the conversion of t.
+// t of a supported kind or a pointer to it is an error because the field of it is converted according to the kind,
+// e.g. type Level int.
+func (r *Registry) Register(t reflect.Type, entry *RegistryEntry) error {
+	if IsSupportedKind(fieldKind(t)) {
+		return Errorf("registry: cannot register %s, the kind %s is converted by itself", t, fieldKind(t))
+	}
+	r.entries[t] = entry
+	return nil
+}
+
+// Lookup returns the conversion of t.
+func (r *Registry) Lookup(t reflect.Type) (*RegistryEntry, bool) {
+	if r == nil || t == nil {
+		return nil, false
+	}
+	x, ok := r.entries[t]
+	return x, ok
+}
+
+// Set converts s by the conversion of the type of v and sets it to v.
+func (r *Registry) Set(v reflect.Value, s string) (bool, error) {
+	entry, ok := r.Lookup(v.Type())
+	if !ok {
+		return false, nil
+	}
+	x, err := entry.Parse(s)
+	if err != nil {
+		return true, err
+	}
+	rx := reflect.ValueOf(x)
+	if !rx.IsValid() || !rx.Type().AssignableTo(v.Type()) {
+		return true, Errorf("registry: cannot set %T to %s", x, v.Type())
+	}
+	v.Set(rx)
+	return true, nil
+}
+
+// Equal reports true if left equals right by the conversion of the type of them.
+// The second result reports whether the conversion is found.
+func (r *Registry) Equal(left, right any) (bool, bool, error) {
+	lType := reflect.TypeOf(left)
+	entry, ok := r.Lookup(lType)
+	if !ok || entry.Equal == nil || lType != reflect.TypeOf(right) {
+		return false, false, nil
+	}
+	eq, err := entry.Equal(left, right)
+	return eq, true, err
+}
@@ -0,0 +1,150 @@
+package internal_test
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+type registryValue struct {
+	values []string
+}
+
+func TestRegistry(t *testing.T) {
+	type T struct {
+		V registryValue `name:"rv" default:"a+b"`
+		I int           `name:"ri" default:"1"`
+	}
+
+	registry := internal.NewRegistry()
+	assert.Nil(t, registry.Register(reflect.TypeFor[registryValue](), &internal.RegistryEntry{
+		Parse: func(s string) (any, error) {
+			return registryValue{values: strings.Split(s, "+")}, nil
+		},
+		Equal: func(left, right any) (bool, error) {
+			return strings.Join(left.(registryValue).values, "+") ==
+				strings.Join(right.(registryValue).values, "+"), nil
+		},
+	}))
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	t.Run("default", func(t *testing.T) {
+		var got T
+		r, err := internal.DefaultReceptor(&got, nil, registry, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			V: registryValue{values: []string{"a", "b"}},
+			I: 1,
+		}, got)
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, registry, nil)))
+		assert.Nil(t, fs.Parse([]string{"--rv", "x+y+z"}))
+
+		var got T
+		r, err := internal.PFlagGetReceptor(&got, fs, nil, registry)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			V: registryValue{values: []string{"x", "y", "z"}},
+			I: 1,
+		}, got)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		m := internal.NewMerger[T](nil, nil, "", registry)
+		got, err := m.Merge(
+			T{
+				V: registryValue{values: []string{"l"}},
+				I: 1,
+			},
+			T{
+				V: registryValue{values: []string{"a", "b"}},
+				I: 2,
+			},
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, T{
+			V: registryValue{values: []string{"l"}},
+			I: 2,
+		}, got)
+	})
+}
+
+func TestRegistryRegisterSupportedKind(t *testing.T) {
+	type level int
+	registry := internal.NewRegistry()
+	for _, typ := range []reflect.Type{
+		reflect.TypeFor[level](),
+		reflect.TypeFor[*level](),
+		reflect.TypeFor[string](),
+	} {
+		t.Run(typ.String(), func(t *testing.T) {
+			err := registry.Register(typ, &internal.RegistryEntry{
+				Parse: func(s string) (any, error) { return nil, nil },
+			})
+			assert.ErrorIs(t, err, internal.ErrStructConfig)
+			_, ok := registry.Lookup(typ)
+			assert.False(t, ok)
+		})
+	}
+}
+
+func TestRegistrySet(t *testing.T) {
+	registry := internal.NewRegistry()
+	assert.Nil(t, registry.Register(reflect.TypeFor[registryValue](), &internal.RegistryEntry{
+		Parse: func(s string) (any, error) {
+			if s == "nil" {
+				return nil, nil
+			}
+			if s == "int" {
+				return 1, nil
+			}
+			return registryValue{values: []string{s}}, nil
+		},
+	}))
+
+	for _, tc := range []struct {
+		title string
+		s     string
+		want  registryValue
+		err   bool
+	}{
+		{
+			title: "set",
+			s:     "a",
+			want:  registryValue{values: []string{"a"}},
+		},
+		{
+			title: "nil",
+			s:     "nil",
+			err:   true,
+		},
+		{
+			title: "another type",
+			s:     "int",
+			err:   true,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			var got registryValue
+			ok, err := registry.Set(reflect.ValueOf(&got).Elem(), tc.s)
+			assert.True(t, ok)
+			if tc.err {
+				assert.ErrorIs(t, err, internal.ErrStructConfig)
+				return
+			}
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, got)
+		})
+	}
+}
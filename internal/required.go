@@ -0,0 +1,68 @@
+package internal
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// CheckRequired reports all fields with the required tag that are missing in one error.
+// The error lists the Go field names, the flag names and the environment variable names, see [FieldEnvVar].
+//
+// isSet reports true if some source set the field.
+// A field is missing if isSet reports false and the field has no default tag.
+// If isSet is nil, a field is missing if the value is zero or empty.
+//
+// ptr should be a pointer of struct.
+func CheckRequired(ptr any, typ *Type, envPrefix string, isSet func(StructField) bool) error {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return JoinErrors(ErrNotStructPointer)
+	}
+
+	missing := []string{}
+	for _, f := range typ.Fields() {
+		if !f.Tag().Required() {
+			continue
+		}
+		if isSet != nil {
+			if _, ok := f.Tag().Default(); ok || isSet(f) {
+				continue
+			}
+		} else if !isMissing(v.Elem().FieldByIndex(f.Index())) {
+			continue
+		}
+		missing = append(missing, describeField(f, envPrefix))
+	}
+
+	if len(missing) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%w: %w: missing %s", ErrStructConfig, ErrRequired, strings.Join(missing, ", "))
+}
+
+// isMissing reports true if v is zero, an empty slice or an empty map.
+func isMissing(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Slice, reflect.Map:
+		return v.Len() == 0
+	default:
+		return v.IsZero()
+	}
+}
+
+// describeField returns the Go field name with the flag name and the environment variable name,
+// e.g. "DB.Host (flag --db.host, env DB_HOST)".
+func describeField(f StructField, envPrefix string) string {
+	sources := []string{}
+	if name, ok := f.Tag().Flag(); ok {
+		sources = append(sources, "flag --"+name)
+	}
+	if env, ok := FieldEnvVar(f, envPrefix); ok {
+		sources = append(sources, "env "+env.String())
+	}
+	if len(sources) == 0 {
+		return f.Name()
+	}
+	return fmt.Sprintf("%s (%s)", f.Name(), strings.Join(sources, ", "))
+}
@@ -0,0 +1,88 @@
+package internal_test
+
+import (
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheckRequired(t *testing.T) {
+	type DB struct {
+		DSN      string `name:"dsn" required:"true"`
+		Password string `name:"password" env:"DB_PASS" required:"true"`
+	}
+	type T struct {
+		Token    string   `name:"token" required:"true"`
+		Port     *int     `name:"port" required:"true"`
+		Hosts    []string `name:"hosts" required:"true"`
+		NoFlag   int      `name:"no_flag" flag:"-" env:"-" required:"true"`
+		Optional string   `name:"optional"`
+		NotTrue  string   `name:"not_true" required:"false"`
+		DB       DB       `name:"db"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		v := T{
+			Token:  "token",
+			Port:   new(0),
+			Hosts:  []string{"a"},
+			NoFlag: 1,
+			DB: DB{
+				DSN:      "dsn",
+				Password: "pass",
+			},
+		}
+		assert.Nil(t, internal.CheckRequired(&v, typ, "", nil))
+	})
+
+	t.Run("missing", func(t *testing.T) {
+		v := T{
+			Hosts: []string{},
+			DB: DB{
+				DSN: "dsn",
+			},
+		}
+		err := internal.CheckRequired(&v, typ, "app_", nil)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorIs(t, err, internal.ErrRequired)
+		assert.Equal(t, "StructConfig: Required: missing "+
+			"Token (flag --token, env APP_TOKEN), "+
+			"Port (flag --port, env APP_PORT), "+
+			"Hosts (flag --hosts, env APP_HOSTS), "+
+			"NoFlag, "+
+			"DB.Password (flag --db.password, env DB_PASS)", err.Error())
+	})
+
+	t.Run("set", func(t *testing.T) {
+		type T struct {
+			Token   string `name:"token" required:"true"`
+			Debug   bool   `name:"debug" required:"true"`
+			Port    int    `name:"port" default:"80" required:"true"`
+			Missing int    `name:"missing" required:"true"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		assert.Nil(t, err)
+		v := T{
+			Token: "token",
+		}
+		set := map[string]bool{
+			"Debug": true, // explicit false
+		}
+		err = internal.CheckRequired(&v, typ, "", func(f internal.StructField) bool {
+			return set[f.Name()]
+		})
+		assert.ErrorIs(t, err, internal.ErrRequired)
+		assert.Equal(t, "StructConfig: Required: missing "+
+			"Token (flag --token, env TOKEN), "+
+			"Missing (flag --missing, env MISSING)", err.Error())
+	})
+
+	t.Run("not pointer", func(t *testing.T) {
+		err := internal.CheckRequired(T{}, typ, "", nil)
+		assert.ErrorIs(t, err, internal.ErrNotStructPointer)
+	})
+}
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"cmp"
+	"slices"
+	"strconv"
+	"strings"
+)
+
+// RedactError masks raw, the value of s, and its elements in the message of err if s has the secret tag.
+// The elements are masked only when they are quoted like strconv.Quote, e.g. parsing "x": invalid syntax,
+// and raw is also masked as a token that is not a part of a word.
+// The returned error wraps err.
+func RedactError(s StructField, raw string, err error) error {
+	if err == nil || !s.Tag().Secret() || raw == "" {
+		return err
+	}
+	return &redactedError{
+		err:    err,
+		raw:    raw,
+		values: secretValues(raw, s.Tag().Sep()),
+	}
+}
+
+// RedactValue returns [SecretMask] if s has the secret tag and v is not empty, otherwise v.
+func RedactValue(s StructField, v string) string {
+	if v != "" && s.Tag().Secret() {
+		return SecretMask
+	}
+	return v
+}
+
+// secretValues returns raw, the elements split by sep and the values of "k=v" elements, the longest first.
+func secretValues(raw, sep string) []string {
+	xs := []string{raw}
+	for x := range strings.SplitSeq(raw, sep) {
+		xs = append(xs, x)
+		if _, v, ok := strings.Cut(x, "="); ok {
+			xs = append(xs, v)
+		}
+	}
+	xs = slices.DeleteFunc(xs, func(x string) bool { return strings.TrimSpace(x) == "" })
+	slices.SortFunc(xs, func(a, b string) int { return cmp.Or(len(b)-len(a), strings.Compare(a, b)) })
+	return slices.Compact(xs)
+}
+
+type redactedError struct {
+	err    error
+	raw    string
+	values []string
+}
+
+func (e *redactedError) Error() string {
+	msg := e.err.Error()
+	for _, v := range e.values {
+		msg = strings.ReplaceAll(msg, strconv.Quote(v), strconv.Quote(SecretMask))
+	}
+	return replaceToken(msg, e.raw, SecretMask)
+}
+
+// replaceToken replaces old in s by new where old is not adjacent to letters, digits or '_'.
+func replaceToken(s, old, new string) string {
+	var (
+		b    strings.Builder
+		last int // the end of the written part of s
+	)
+	for start := 0; ; {
+		i := strings.Index(s[start:], old)
+		if i < 0 {
+			b.WriteString(s[last:])
+			return b.String()
+		}
+		i += start
+		j := i + len(old)
+		if isWordBoundary(s, i-1) && isWordBoundary(s, j) {
+			b.WriteString(s[last:i])
+			b.WriteString(new)
+			last = j
+		}
+		start = j
+	}
+}
+
+// isWordBoundary reports true if s[i] is out of s or not a letter, a digit or '_'.
+func isWordBoundary(s string, i int) bool {
+	return i < 0 || i >= len(s) || !isExpandNameChar(s[i])
+}
+
+func (e *redactedError) Unwrap() error { return e.err }
@@ -0,0 +1,161 @@
+package internal_test
+
+import (
+	"errors"
+	"log/slog"
+	"strconv"
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRedactError(t *testing.T) {
+	type T struct {
+		Token  string         `name:"token" secret:"true"`
+		Plain  string         `name:"plain"`
+		Secret string         `name:"secret" secret:"false"`
+		Pins   map[string]int `name:"pins" secret:"true"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+	fields := map[string]internal.StructField{}
+	for _, f := range typ.Fields() {
+		fields[f.Name()] = f
+	}
+
+	errSome := errors.New(`parsing "hunter2": invalid`)
+	for _, tc := range []struct {
+		title string
+		field string
+		raw   string
+		err   error
+		want  string
+	}{
+		{
+			title: "secret",
+			field: "Token",
+			raw:   "hunter2",
+			err:   errSome,
+			want:  `parsing "******": invalid`,
+		},
+		{
+			title: "not secret",
+			field: "Plain",
+			raw:   "hunter2",
+			err:   errSome,
+			want:  `parsing "hunter2": invalid`,
+		},
+		{
+			title: "secret false",
+			field: "Secret",
+			raw:   "hunter2",
+			err:   errSome,
+			want:  `parsing "hunter2": invalid`,
+		},
+		{
+			title: "elements",
+			field: "Pins",
+			raw:   "a=1234,b=x56",
+			err:   errors.New(`parsing "x56": invalid syntax`),
+			want:  `parsing "******": invalid syntax`,
+		},
+		{
+			title: "unquoted elements",
+			field: "Token",
+			raw:   "a,b",
+			err:   errors.New(`parsing "b": invalid at a`),
+			want:  `parsing "******": invalid at a`,
+		},
+		{
+			title: "token",
+			field: "Token",
+			raw:   "hunter2",
+			err:   errors.New(`token hunter2 rejected, hunter22 and hunter2hunter2 are not`),
+			want:  `token ****** rejected, hunter22 and hunter2hunter2 are not`,
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got := internal.RedactError(fields[tc.field], tc.raw, tc.err)
+			assert.ErrorIs(t, got, tc.err)
+			assert.Equal(t, tc.want, got.Error())
+		})
+	}
+
+	assert.Nil(t, internal.RedactError(fields["Token"], "hunter2", nil))
+}
+
+func TestSecretEnvReceptor(t *testing.T) {
+	type T struct {
+		PIN   int        `name:"pin" secret:"true"`
+		Level slog.Level `name:"level" secret:"true"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	for _, tc := range []struct {
+		title   string
+		environ []string
+		raw     string
+	}{
+		{
+			title:   "int",
+			environ: []string{"PIN=12x4"},
+			raw:     "12x4",
+		},
+		{
+			title:   "text unmarshaler",
+			environ: []string{"LEVEL=hunter2"},
+			raw:     "hunter2",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			var got T
+			r, err := internal.EnvReceptor(&got, "", internal.EnvFileNone, internal.EnvironLookup(tc.environ), nil, nil, nil, nil)
+			assert.Nil(t, err)
+			err = typ.Accept(r)
+			assert.NotNil(t, err)
+			assert.NotContains(t, err.Error(), tc.raw)
+			assert.Contains(t, err.Error(), internal.SecretMask)
+		})
+	}
+
+	t.Run("wrapped error", func(t *testing.T) {
+		var got T
+		r, err := internal.EnvReceptor(&got, "", internal.EnvFileNone, internal.EnvironLookup([]string{"PIN=12x4"}), nil, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.ErrorIs(t, typ.Accept(r), strconv.ErrSyntax)
+	})
+}
+
+func TestSecretValidationAndExplain(t *testing.T) {
+	type T struct {
+		Token string `name:"token" default:"changeme" secret:"true" pattern:"^[a-z]+$" minlen:"10"`
+		PIN   int    `name:"pin" secret:"true" max:"9999"`
+		Empty string `name:"empty" secret:"true"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	v := T{
+		Token: "Hunter2",
+		PIN:   123456,
+	}
+
+	r, err := internal.NewValidationReceptor(&v, "", nil)
+	assert.Nil(t, err)
+	assert.Nil(t, typ.Accept(r))
+	assert.Equal(t, "StructConfig: Validation: "+
+		"Token (flag --token, env TOKEN): length 7 is less than minlen 10; "+
+		`Token (flag --token, env TOKEN): "******" does not match ^[a-z]+$; `+
+		"PIN (flag --pin, env PIN): ****** is greater than max 9999",
+		r.Err().Error())
+
+	xs, err := internal.Explain(&v, typ, "")
+	assert.Nil(t, err)
+	assert.Equal(t, []*internal.Explanation{
+		{Field: "Token", Flag: "token", Env: "TOKEN", Value: "******", Default: "******"},
+		{Field: "PIN", Flag: "pin", Env: "PIN", Value: "******"},
+		{Field: "Empty", Flag: "empty", Env: "EMPTY", Value: ""},
+	}, xs)
+}
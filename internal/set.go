@@ -8,6 +8,12 @@ import "reflect"
 // get should return a value to be set; false means not found.
 // converter should convert result of get().
 //
+// The conversions in registry are used first for the fields passed to [AnyReceptor], registry can be nil.
+// A slice of a supported kind is split by the sep tag value, see [Tag.Sep].
+// A map from string to a supported kind is parsed like "k1=v1,k2=v2", the sep tag value separates the elements.
+// [IsTimeType] values are parsed by [SetTime] with the layout tag value, see [Tag.Layout].
+// [IsTextType] values are parsed by [encoding.TextUnmarshaler].
+//
 // You can customize how unsupported field values are set by anyCallback,
 // if nil, disable this feature.
 // Example of anyCallback:
@@ -27,8 +33,9 @@ func SetReceptor(
 	get func(StructField) (string, error),
 	converter Converter,
 	anyCallback func(StructField, string, func() reflect.Value) error,
+	registry *Registry,
 ) (*PairsReceptor, error) {
-	typedReceptor, err := SetTypedReceptor(ptr, anyCallback)
+	typedReceptor, err := SetTypedReceptor(ptr, anyCallback, registry)
 	if err != nil {
 		return nil, err
 	}
@@ -38,6 +45,7 @@ func SetReceptor(
 func SetTypedReceptor(
 	ptr any,
 	anyCallback func(StructField, string, func() reflect.Value) error,
+	registry *Registry,
 ) (*DefaultTypedReceptor, error) {
 	typ := reflect.TypeOf(ptr)
 	if !(typ.Kind() == reflect.Pointer && typ.Elem().Kind() == reflect.Struct) {
@@ -45,8 +53,11 @@ func SetTypedReceptor(
 	}
 
 	v := reflect.ValueOf(ptr)
+	field := func(s StructField) reflect.Value {
+		return v.Elem().FieldByIndex(s.Index())
+	}
 	fv := func(s StructField) reflect.Value {
-		return v.Elem().FieldByName(s.Name())
+		return allocElem(field(s))
 	}
 
 	return &DefaultTypedReceptor{
@@ -107,14 +118,45 @@ func SetTypedReceptor(
 			return nil
 		},
 		AnyFunc: func(s StructField, v string) error {
+			if ok, err := registry.Set(field(s), v); ok {
+				return err
+			}
+			if IsTimeType(s.FieldType()) {
+				return SetTime(field(s), v, s.Tag().Layout())
+			}
+			if IsTextType(s.FieldType()) {
+				return SetText(field(s), v)
+			}
+			if IsSupportedSlice(s.FieldType()) {
+				return SetSlice(field(s), SplitSlice(v, s.Tag().Sep()))
+			}
+			if IsSupportedMap(s.FieldType()) {
+				m, err := SplitMap(v, s.Tag().Sep())
+				if err != nil {
+					return err
+				}
+				return SetMap(field(s), m)
+			}
 			if anyCallback == nil {
 				return nil
 			}
 			return anyCallback(
 				s,
 				v,
-				func() reflect.Value { return fv(s) },
+				func() reflect.Value { return field(s) },
 			)
 		},
 	}, nil
 }
+
+// allocElem returns the element of v if v is a pointer, allocating it if nil.
+// Otherwise returns v.
+func allocElem(v reflect.Value) reflect.Value {
+	if v.Kind() != reflect.Pointer {
+		return v
+	}
+	if v.IsNil() {
+		v.Set(reflect.New(v.Type().Elem()))
+	}
+	return v.Elem()
+}
@@ -0,0 +1,53 @@
+package internal
+
+import (
+	"reflect"
+	"strings"
+)
+
+// IsSupportedSlice reports true if t is a slice of a supported kind.
+func IsSupportedSlice(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Slice && IsSupportedKind(t.Elem().Kind())
+}
+
+// SplitSlice splits s by sep.
+// Empty s is an empty slice.
+func SplitSlice(s, sep string) []string {
+	if s == "" {
+		return []string{}
+	}
+	return strings.Split(s, sep)
+}
+
+// SetSlice converts xs into the elements of the slice v by [NewConv] and sets them to v, see [ConvertValue].
+func SetSlice(v reflect.Value, xs []string) error {
+	r := reflect.MakeSlice(v.Type(), len(xs), len(xs))
+	for i, x := range xs {
+		if err := ConvertValue(NewConv(), r.Index(i), x); err != nil {
+			return err
+		}
+	}
+	v.Set(r)
+	return nil
+}
+
+// ParseSlice converts xs into []T.
+func ParseSlice[T any](xs []string) ([]T, error) {
+	var r []T
+	if err := SetSlice(reflect.ValueOf(&r).Elem(), xs); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func equalSlice(left, right reflect.Value) bool {
+	if left.Len() != right.Len() {
+		return false
+	}
+	for i := range left.Len() {
+		if left.Index(i).Interface() != right.Index(i).Interface() {
+			return false
+		}
+	}
+	return true
+}
@@ -3,7 +3,11 @@ package internal
 import "reflect"
 
 // Call calls the appropriate method of r for the kind of f.
+// [IsTimeType] and [IsTextType] fields are passed to [AnyReceptor] regardless of the kind.
 func Call(r Receptor, f StructField) error {
+	if IsTimeType(f.FieldType()) || IsTextType(f.FieldType()) {
+		return r.Any(f)
+	}
 	return Switch(r, f.Kind())(f)
 }
 
@@ -3,19 +3,46 @@ package internal
 import (
 	"fmt"
 	"reflect"
+	"strconv"
+	"strings"
 )
 
 const (
-	TagName    = "name"
-	TagUsage   = "usage"
-	TagDefault = "default"
-	TagShort   = "short"
+	TagName     = "name"
+	TagUsage    = "usage"
+	TagDefault  = "default"
+	TagShort    = "short"
+	TagSep      = "sep"
+	TagLayout   = "layout"
+	TagEnv      = "env"
+	TagFlag     = "flag"
+	TagKey      = "key"
+	TagRequired = "required"
+	TagMin      = "min"
+	TagMax      = "max"
+	TagOneOf    = "oneof"
+	TagPattern  = "pattern"
+	TagMinLen   = "minlen"
+	TagMaxLen   = "maxlen"
+	TagSecret   = "secret"
+
+	// OneOfSeparator separates the values of the oneof tag.
+	OneOfSeparator = "|"
 
 	TagNameIgnored = "-"
+
+	// DefaultSep is the default separator of the elements of slices.
+	DefaultSep = ","
+
+	// NameSeparator joins the name of a nested struct field and the names of its fields.
+	NameSeparator = "."
+
+	// SecretMask replaces the values of the fields with the secret tag.
+	SecretMask = "******"
 )
 
 // NewTag returns a new [Tag].
-// [Tag] retrieves values of all [TagName], [TagUsage], [TagDefault].
+// [Tag] retrieves values of all [TagName], [TagUsage], [TagDefault] and so on.
 // prefix adds a prefix to the tag names.
 func NewTag(t reflect.StructTag, prefix string) *Tag {
 	return &Tag{
@@ -26,17 +53,106 @@ func NewTag(t reflect.StructTag, prefix string) *Tag {
 
 // Tag is a tag of struct.
 type Tag struct {
-	tag    reflect.StructTag
-	prefix string
+	tag       reflect.StructTag
+	prefix    string
+	namespace string
+}
+
+// WithNamespace returns a copy of the tag whose [Tag.Name] is prefixed by namespace and [NameSeparator].
+func (t Tag) WithNamespace(namespace string) *Tag {
+	t.namespace = namespace
+	return &t
 }
 
+// Name returns the name tag value.
+// If the tag belongs to a field of a nested struct, the value is joined with the names of the parents,
+// e.g. "db.host".
 func (t Tag) Name() (string, bool) {
 	if v := t.tag.Get(t.prefix + TagName); v != "" && v != TagNameIgnored {
+		if t.namespace != "" {
+			return t.namespace + NameSeparator + v, true
+		}
 		return v, true
 	}
 	return "", false
 }
 
+// Flag returns the flag name.
+// It is the flag tag value if exists, otherwise [Tag.Name].
+// The flag tag value "-" means that the field has no flag.
+func (t Tag) Flag() (string, bool) {
+	return t.override(TagFlag)
+}
+
+// Key returns the key of configuration files.
+// It is the key tag value if exists, otherwise [Tag.Name].
+// The key tag value "-" means that the field is not read from files.
+func (t Tag) Key() (string, bool) {
+	return t.override(TagKey)
+}
+
+// Env returns the env tag value, the name of the environment variable used as is.
+// The env tag value "-" means that the field is not read from environment variables.
+func (t Tag) Env() (string, bool) {
+	v := t.tag.Get(t.prefix + TagEnv)
+	return v, v != ""
+}
+
+func (t Tag) override(key string) (string, bool) {
+	name, ok := t.Name()
+	if !ok {
+		return "", false
+	}
+	switch v := t.tag.Get(t.prefix + key); v {
+	case "":
+		return name, true
+	case TagNameIgnored:
+		return "", false
+	default:
+		return v, true
+	}
+}
+
+// Required reports true if the required tag value is true.
+func (t Tag) Required() bool {
+	v, _ := strconv.ParseBool(t.tag.Get(t.prefix + TagRequired))
+	return v
+}
+
+// Secret reports true if the secret tag value is true.
+func (t Tag) Secret() bool {
+	v, _ := strconv.ParseBool(t.tag.Get(t.prefix + TagSecret))
+	return v
+}
+
+// Min returns the min tag value, the lower bound of numbers and durations.
+func (t Tag) Min() (string, bool) { return t.lookup(TagMin) }
+
+// Max returns the max tag value, the upper bound of numbers and durations.
+func (t Tag) Max() (string, bool) { return t.lookup(TagMax) }
+
+// OneOf returns the oneof tag value split by [OneOfSeparator], the allowed values.
+func (t Tag) OneOf() ([]string, bool) {
+	v, ok := t.lookup(TagOneOf)
+	if !ok {
+		return nil, false
+	}
+	return strings.Split(v, OneOfSeparator), true
+}
+
+// Pattern returns the pattern tag value, the regular expression that strings should match.
+func (t Tag) Pattern() (string, bool) { return t.lookup(TagPattern) }
+
+// MinLen returns the minlen tag value, the lower bound of the length of strings, slices and maps.
+func (t Tag) MinLen() (string, bool) { return t.lookup(TagMinLen) }
+
+// MaxLen returns the maxlen tag value, the upper bound of the length of strings, slices and maps.
+func (t Tag) MaxLen() (string, bool) { return t.lookup(TagMaxLen) }
+
+func (t Tag) lookup(key string) (string, bool) {
+	return t.tag.Lookup(t.prefix + key)
+}
+
 func (t Tag) Usage() string {
 	return t.tag.Get(t.prefix + TagUsage)
 }
@@ -49,6 +165,27 @@ func (t Tag) Short() (string, bool) {
 	return t.tag.Lookup(t.prefix + TagShort)
 }
 
+// Sep returns the separator of the elements of slices.
+// Default is [DefaultSep].
+func (t Tag) Sep() string {
+	if v := t.tag.Get(t.prefix + TagSep); v != "" {
+		return v
+	}
+	return DefaultSep
+}
+
+// Layout returns the layout to parse [time.Time].
+// Default is [DefaultLayout].
+func (t Tag) Layout() string {
+	if v := t.tag.Get(t.prefix + TagLayout); v != "" {
+		return v
+	}
+	return DefaultLayout
+}
+
 func (t Tag) String() string {
+	if t.namespace != "" {
+		return fmt.Sprintf("tag=%s prefix=%s namespace=%s", t.tag, t.prefix, t.namespace)
+	}
 	return fmt.Sprintf("tag=%s prefix=%s", t.tag, t.prefix)
 }
@@ -65,4 +65,46 @@ func TestTag(t *testing.T) {
 		_, ok := x.Name()
 		assert.False(t, ok)
 	})
+	t.Run("namespace", func(t *testing.T) {
+		x := internal.NewTag(v.Field(0).Tag, "").WithNamespace("db")
+		name, ok := x.Name()
+		assert.True(t, ok)
+		assert.Equal(t, "db.t1", name)
+	})
+}
+
+func TestTagOverride(t *testing.T) {
+	type A struct {
+		Plain    int `name:"plain"`
+		Override int `name:"db.host" env:"DATABASE_URL_HOST" flag:"db-host" key:"database.host"`
+		Ignored  int `name:"ignored" env:"-" flag:"-" key:"-"`
+		NoName   int `flag:"no-name"`
+	}
+
+	v := reflect.TypeFor[A]()
+	for _, tc := range []struct {
+		title string
+		index int
+		env   string
+		flag  string
+		key   string
+	}{
+		{title: "plain", index: 0, flag: "plain", key: "plain"},
+		{title: "override", index: 1, env: "DATABASE_URL_HOST", flag: "db-host", key: "database.host"},
+		{title: "ignored", index: 2, env: "-"},
+		{title: "no name", index: 3},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			x := internal.NewTag(v.Field(tc.index).Tag, "")
+			env, ok := x.Env()
+			assert.Equal(t, tc.env != "", ok)
+			assert.Equal(t, tc.env, env)
+			flag, ok := x.Flag()
+			assert.Equal(t, tc.flag != "", ok)
+			assert.Equal(t, tc.flag, flag)
+			key, ok := x.Key()
+			assert.Equal(t, tc.key != "", ok)
+			assert.Equal(t, tc.key, key)
+		})
+	}
 }
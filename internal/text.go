@@ -0,0 +1,119 @@
+package internal
+
+import (
+	"bytes"
+	"encoding"
+	"reflect"
+
+	"github.com/spf13/pflag"
+)
+
+var textUnmarshalerType = reflect.TypeFor[encoding.TextUnmarshaler]()
+
+// IsTextType reports true if the pointer to t implements [encoding.TextUnmarshaler].
+// If t is a pointer, reports on the element of t.
+func IsTextType(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	return reflect.PointerTo(t).Implements(textUnmarshalerType)
+}
+
+// SetText sets s to v by [encoding.TextUnmarshaler].
+// The type of v should satisfy [IsTextType].
+func SetText(v reflect.Value, s string) error {
+	v = allocElem(v)
+	return v.Addr().Interface().(encoding.TextUnmarshaler).UnmarshalText([]byte(s))
+}
+
+// equalText compares the values of [IsTextType], the pointers are compared by the elements.
+func equalText(left, right any) (bool, bool) {
+	lType := reflect.TypeOf(left)
+	if !IsTextType(lType) || lType != reflect.TypeOf(right) {
+		return false, false
+	}
+	if lType.Kind() == reflect.Pointer {
+		l, r := reflect.ValueOf(left), reflect.ValueOf(right)
+		if l.IsNil() || r.IsNil() {
+			return l.IsNil() == r.IsNil(), true
+		}
+		return equalText(l.Elem().Interface(), r.Elem().Interface())
+	}
+	if lType.Comparable() {
+		return left == right, true
+	}
+	l, ok := left.(encoding.TextMarshaler)
+	if !ok {
+		return false, false
+	}
+	lb, err := l.MarshalText()
+	if err != nil {
+		return false, false
+	}
+	rb, err := right.(encoding.TextMarshaler).MarshalText()
+	if err != nil {
+		return false, false
+	}
+	return bytes.Equal(lb, rb), true
+}
+
+var _ pflag.Value = &textValue{}
+
+// textValue is a [pflag.Value] of [IsTextType].
+type textValue struct {
+	ptr   reflect.Value // pointer to the value
+	raw   string
+	isNil bool // the value of a pointer field is not given yet
+}
+
+// newTextValue returns a [textValue] for t.
+// If t is a pointer and defaultValue is empty, the value is nil until set.
+func newTextValue(t reflect.Type, defaultValue string) (*textValue, error) {
+	var isNil bool
+	if t.Kind() == reflect.Pointer {
+		t = t.Elem()
+		isNil = defaultValue == ""
+	}
+	v := &textValue{
+		ptr:   reflect.New(t),
+		isNil: isNil,
+	}
+	if defaultValue != "" {
+		if err := v.Set(defaultValue); err != nil {
+			return nil, err
+		}
+	}
+	return v, nil
+}
+
+func (v *textValue) Set(s string) error {
+	v.raw = s
+	v.isNil = false
+	return SetText(v.ptr.Elem(), s)
+}
+
+// String returns the result of [encoding.TextMarshaler] if implemented, otherwise the raw value.
+// It is empty if the value is nil.
+func (v *textValue) String() string {
+	if v.isNil {
+		return ""
+	}
+	if m, ok := v.ptr.Interface().(encoding.TextMarshaler); ok {
+		if b, err := m.MarshalText(); err == nil {
+			return string(b)
+		}
+	}
+	return v.raw
+}
+
+func (v *textValue) Type() string {
+	return v.ptr.Type().Elem().Name()
+}
+
+// setTo sets the value to dst.
+func (v *textValue) setTo(dst reflect.Value) {
+	allocElem(dst).Set(v.ptr.Elem())
+}
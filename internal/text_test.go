@@ -0,0 +1,146 @@
+package internal_test
+
+import (
+	"log/slog"
+	"net/netip"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+type textID struct {
+	value string
+}
+
+func (t *textID) UnmarshalText(b []byte) error {
+	t.value = strings.TrimPrefix(string(b), "id-")
+	return nil
+}
+
+func TestTextType(t *testing.T) {
+	type T struct {
+		L  slog.Level  `name:"xl" default:"warn"`
+		PL *slog.Level `name:"xpl"`
+		A  netip.Addr  `name:"xa" default:"127.0.0.1"`
+		ID textID      `name:"xid"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	defaultValue := T{
+		L: slog.LevelWarn,
+		A: netip.MustParseAddr("127.0.0.1"),
+	}
+
+	t.Run("default", func(t *testing.T) {
+		var got T
+		r, err := internal.DefaultReceptor(&got, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, defaultValue, got)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		envs := map[string]string{
+			"XPL": "debug",
+			"XID": "id-1",
+		}
+		for k, v := range envs {
+			os.Setenv(k, v)
+		}
+		defer func() {
+			for k := range envs {
+				os.Unsetenv(k)
+			}
+		}()
+
+		var got T
+		r, err := internal.EnvReceptor(&got, "", internal.EnvFileNone, nil, nil, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			L:  slog.LevelWarn,
+			PL: new(slog.LevelDebug),
+			A:  defaultValue.A,
+			ID: textID{value: "1"},
+		}, got)
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		for _, tc := range []struct {
+			title string
+			args  []string
+			want  T
+		}{
+			{
+				title: "default",
+				want:  defaultValue,
+			},
+			{
+				title: "change all",
+				args: []string{
+					"--xl", "error",
+					"--xpl", "info",
+					"--xa", "::1",
+					"--xid", "id-2",
+				},
+				want: T{
+					L:  slog.LevelError,
+					PL: new(slog.LevelInfo),
+					A:  netip.MustParseAddr("::1"),
+					ID: textID{value: "2"},
+				},
+			},
+		} {
+			t.Run(tc.title, func(t *testing.T) {
+				fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+				assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, nil, nil)))
+				assert.Equal(t, "WARN", fs.Lookup("xl").DefValue)
+				assert.Equal(t, "", fs.Lookup("xpl").DefValue, "nil without default")
+				assert.NotContains(t, fs.FlagUsages(), "(default INFO)")
+				assert.Nil(t, fs.Parse(tc.args))
+
+				var got T
+				r, err := internal.PFlagGetReceptor(&got, fs, nil, nil)
+				assert.Nil(t, err)
+				assert.Nil(t, typ.Accept(r))
+				assert.Equal(t, tc.want, got)
+			})
+		}
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		m := internal.NewMerger[T](nil, nil, "", nil)
+		got, err := m.Merge(
+			T{
+				L: slog.LevelWarn,
+				A: netip.MustParseAddr("::1"),
+			},
+			T{
+				L: slog.LevelError,
+				A: defaultValue.A,
+			},
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, T{
+			L: slog.LevelError,
+			A: netip.MustParseAddr("::1"),
+		}, got)
+	})
+	t.Run("merge pointer", func(t *testing.T) {
+		type T struct {
+			A *netip.Addr `name:"xa" default:"127.0.0.1"`
+		}
+		m := internal.NewMerger[T](nil, nil, "", nil)
+		left := netip.MustParseAddr("::1")
+		right := netip.MustParseAddr("127.0.0.1") // equals the default
+		got, err := m.Merge(T{A: &left}, T{A: &right})
+		assert.Nil(t, err)
+		assert.Equal(t, T{A: &left}, got)
+	})
+}
@@ -0,0 +1,83 @@
+package internal
+
+import (
+	"reflect"
+	"time"
+)
+
+// DefaultLayout is the default layout to parse [time.Time].
+const DefaultLayout = time.RFC3339
+
+var (
+	durationType = reflect.TypeFor[time.Duration]()
+	timeType     = reflect.TypeFor[time.Time]()
+	locationType = reflect.TypeFor[*time.Location]()
+)
+
+// IsTimeType reports true if t is [time.Duration], [time.Time], *[time.Location]
+// or a pointer to [time.Duration] or [time.Time].
+func IsTimeType(t reflect.Type) bool {
+	if t == nil {
+		return false
+	}
+	switch t {
+	case durationType, timeType, locationType:
+		return true
+	}
+	return t.Kind() == reflect.Pointer && (t.Elem() == durationType || t.Elem() == timeType)
+}
+
+// SetTime converts s and sets it to v.
+//
+// The type of v should satisfy [IsTimeType].
+// [time.Duration] is parsed by [time.ParseDuration],
+// [time.Time] is parsed by [time.Parse] with layout,
+// *[time.Location] is loaded by [time.LoadLocation].
+func SetTime(v reflect.Value, s, layout string) error {
+	switch v.Type() {
+	case durationType:
+		x, err := time.ParseDuration(s)
+		if err != nil {
+			return err
+		}
+		v.SetInt(int64(x))
+	case timeType:
+		x, err := time.Parse(layout, s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(x))
+	case locationType:
+		x, err := time.LoadLocation(s)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(x))
+	default:
+		if v.Kind() == reflect.Pointer {
+			return SetTime(allocElem(v), s, layout)
+		}
+		return Errorf("cannot set %s to %s", s, v.Type())
+	}
+	return nil
+}
+
+func equalTime(left, right any) (bool, bool) {
+	switch l := left.(type) {
+	case time.Time:
+		r, ok := right.(time.Time)
+		return ok && l.Equal(r), true
+	case *time.Location:
+		r, ok := right.(*time.Location)
+		if !ok {
+			return false, true
+		}
+		if l == nil || r == nil {
+			// nil is not UTC though String returns "UTC"
+			return l == r, true
+		}
+		return l.String() == r.String(), true
+	default:
+		return false, false
+	}
+}
@@ -0,0 +1,203 @@
+package internal_test
+
+import (
+	"os"
+	"testing"
+	"time"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/spf13/pflag"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTimeType(t *testing.T) {
+	type T struct {
+		D  time.Duration  `name:"td" default:"5s"`
+		PD *time.Duration `name:"tpd"`
+		T  time.Time      `name:"tt" default:"2024-01-02T03:04:05Z"`
+		L  time.Time      `name:"tl" default:"2024-01-02" layout:"2006-01-02"`
+		Z  *time.Location `name:"tz" default:"UTC"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	tokyo, err := time.LoadLocation("Asia/Tokyo")
+	if err != nil {
+		t.Skip(err)
+	}
+	defaultValue := T{
+		D: 5 * time.Second,
+		T: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+		L: time.Date(2024, 1, 2, 0, 0, 0, 0, time.UTC),
+		Z: time.UTC,
+	}
+
+	t.Run("default", func(t *testing.T) {
+		var got T
+		r, err := internal.DefaultReceptor(&got, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, defaultValue, got)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		envs := map[string]string{
+			"TD":  "1m",
+			"TPD": "2h",
+			"TL":  "2025-03-04",
+			"TZ":  "Asia/Tokyo",
+		}
+		for k, v := range envs {
+			os.Setenv(k, v)
+		}
+		defer func() {
+			for k := range envs {
+				os.Unsetenv(k)
+			}
+		}()
+
+		var got T
+		r, err := internal.EnvReceptor(&got, "", internal.EnvFileNone, nil, nil, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			D:  time.Minute,
+			PD: new(2 * time.Hour),
+			T:  defaultValue.T,
+			L:  time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+			Z:  tokyo,
+		}, got)
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		for _, tc := range []struct {
+			title string
+			args  []string
+			want  T
+		}{
+			{
+				title: "default",
+				want:  defaultValue,
+			},
+			{
+				title: "change all",
+				args: []string{
+					"--td", "1m",
+					"--tpd", "0s",
+					"--tt", "2025-03-04T05:06:07Z",
+					"--tl", "2025-03-04",
+					"--tz", "Asia/Tokyo",
+				},
+				want: T{
+					D:  time.Minute,
+					PD: new(time.Duration(0)),
+					T:  time.Date(2025, 3, 4, 5, 6, 7, 0, time.UTC),
+					L:  time.Date(2025, 3, 4, 0, 0, 0, 0, time.UTC),
+					Z:  tokyo,
+				},
+			},
+		} {
+			t.Run(tc.title, func(t *testing.T) {
+				fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+				assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, nil, nil)))
+				assert.Nil(t, fs.Parse(tc.args))
+
+				var got T
+				r, err := internal.PFlagGetReceptor(&got, fs, nil, nil)
+				assert.Nil(t, err)
+				assert.Nil(t, typ.Accept(r))
+				assert.Equal(t, tc.want, got)
+			})
+		}
+	})
+
+	t.Run("flag location without default", func(t *testing.T) {
+		type T struct {
+			Z *time.Location `name:"tz"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		assert.Nil(t, err)
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, nil, nil)))
+		assert.Nil(t, fs.Parse(nil))
+
+		var got T
+		r, err := internal.PFlagGetReceptor(&got, fs, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Nil(t, got.Z)
+	})
+
+	t.Run("merge", func(t *testing.T) {
+		m := internal.NewMerger[T](nil, nil, "", nil)
+		got, err := m.Merge(
+			T{
+				D: time.Minute,
+				T: defaultValue.T.In(tokyo), // equals the default
+				L: defaultValue.L,
+			},
+			T{
+				D: 5 * time.Second,
+				T: defaultValue.T,
+				L: defaultValue.L,
+				Z: tokyo,
+			},
+		)
+		assert.Nil(t, err)
+		assert.Equal(t, T{
+			D: time.Minute,
+			T: defaultValue.T,
+			L: defaultValue.L,
+			Z: tokyo,
+		}, got)
+	})
+}
+
+func TestDurationSlice(t *testing.T) {
+	type T struct {
+		DS []time.Duration          `name:"ds" default:"1s,2m"`
+		DM map[string]time.Duration `name:"dm" default:"a=1s"`
+	}
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	t.Run("default", func(t *testing.T) {
+		var got T
+		r, err := internal.DefaultReceptor(&got, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			DS: []time.Duration{time.Second, 2 * time.Minute},
+			DM: map[string]time.Duration{"a": time.Second},
+		}, got)
+	})
+
+	t.Run("env", func(t *testing.T) {
+		var got T
+		r, err := internal.EnvReceptor(&got, "", internal.EnvFileNone,
+			internal.EnvironLookup([]string{"DS=1s,2s", "DM=a=3h,b=4ms"}), nil, nil, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			DS: []time.Duration{time.Second, 2 * time.Second},
+			DM: map[string]time.Duration{"a": 3 * time.Hour, "b": 4 * time.Millisecond},
+		}, got)
+	})
+
+	t.Run("flag", func(t *testing.T) {
+		fs := pflag.NewFlagSet("test", pflag.ContinueOnError)
+		assert.Nil(t, typ.Accept(internal.PFlagSetReceptor(fs, nil, nil)))
+		assert.Equal(t, "durationSlice", fs.Lookup("ds").Value.Type())
+		assert.Nil(t, fs.Parse([]string{"--ds", "3s,4m", "--ds", "5h", "--dm", "b=1m"}))
+
+		var got T
+		r, err := internal.PFlagGetReceptor(&got, fs, nil, nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, T{
+			DS: []time.Duration{3 * time.Second, 4 * time.Minute, 5 * time.Hour},
+			DM: map[string]time.Duration{"b": time.Minute},
+		}, got)
+	})
+}
@@ -0,0 +1,666 @@
+package internal
+
+import (
+	"io"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// DecodeTOML decodes a TOML document into [Document].
+// The values are wrapped by [*DocumentNode] to hold the line and the column.
+//
+// Tables are decoded into [Document], arrays of tables into []any of [Document].
+// Integers are int64, floats are float64 and date-times are strings.
+func DecodeTOML(r io.Reader) (Document, error) {
+	b, err := io.ReadAll(r)
+	if err != nil {
+		return nil, JoinErrors(Errorf("decode toml"), err)
+	}
+	p := newTOMLParser(string(b))
+	if err := p.parse(); err != nil {
+		return nil, err
+	}
+	return p.root, nil
+}
+
+type tomlParser struct {
+	src  string
+	pos  int
+	line int
+	col  int
+
+	root    Document
+	current Document
+	// defined is the set of the tables defined by the headers.
+	defined map[*DocumentNode]bool
+	// arrayTables is the set of the arrays defined by the array of tables headers.
+	arrayTables map[*DocumentNode]bool
+	// inline is the set of the inline tables and the static arrays, they cannot be extended.
+	inline map[*DocumentNode]bool
+}
+
+func newTOMLParser(src string) *tomlParser {
+	root := Document{}
+	return &tomlParser{
+		src:         strings.TrimPrefix(src, "\ufeff"),
+		line:        1,
+		col:         1,
+		root:        root,
+		current:     root,
+		defined:     map[*DocumentNode]bool{},
+		arrayTables: map[*DocumentNode]bool{},
+		inline:      map[*DocumentNode]bool{},
+	}
+}
+
+func (p *tomlParser) errorf(format string, v ...any) error {
+	return p.errorAt(p.line, p.col, format, v...)
+}
+
+func (p *tomlParser) errorAt(line, col int, format string, v ...any) error {
+	return Errorf("decode toml: line %d, column %d: "+format, append([]any{line, col}, v...)...)
+}
+
+func (p *tomlParser) eof() bool { return p.pos >= len(p.src) }
+
+func (p *tomlParser) peek() byte {
+	if p.eof() {
+		return 0
+	}
+	return p.src[p.pos]
+}
+
+func (p *tomlParser) hasPrefix(s string) bool {
+	return strings.HasPrefix(p.src[p.pos:], s)
+}
+
+func (p *tomlParser) next() byte {
+	c := p.src[p.pos]
+	p.pos++
+	switch {
+	case c == '\n':
+		p.line++
+		p.col = 1
+	case c&0xC0 != 0x80: // not a continuation byte of UTF-8
+		p.col++
+	}
+	return c
+}
+
+func (p *tomlParser) skip(n int) {
+	for range n {
+		p.next()
+	}
+}
+
+// skipSpace skips spaces and tabs.
+func (p *tomlParser) skipSpace() {
+	for c := p.peek(); c == ' ' || c == '\t'; c = p.peek() {
+		p.next()
+	}
+}
+
+func (p *tomlParser) skipComment() {
+	if p.peek() != '#' {
+		return
+	}
+	for !p.eof() && p.peek() != '\n' {
+		p.next()
+	}
+}
+
+// skipBlank skips whitespaces, newlines and comments.
+func (p *tomlParser) skipBlank() {
+	for {
+		p.skipSpace()
+		p.skipComment()
+		switch {
+		case p.hasPrefix("\n"):
+			p.next()
+		case p.hasPrefix("\r\n"):
+			p.skip(2)
+		default:
+			return
+		}
+	}
+}
+
+func (p *tomlParser) expectEOL() error {
+	p.skipSpace()
+	p.skipComment()
+	switch {
+	case p.eof():
+		return nil
+	case p.hasPrefix("\n"):
+		p.next()
+		return nil
+	case p.hasPrefix("\r\n"):
+		p.skip(2)
+		return nil
+	default:
+		return p.errorf("unexpected %q", p.peek())
+	}
+}
+
+func (p *tomlParser) parse() error {
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil
+		}
+		var err error
+		if p.peek() == '[' {
+			err = p.parseTableHeader()
+		} else {
+			err = p.parseKeyValue(p.current)
+		}
+		if err != nil {
+			return err
+		}
+		if err := p.expectEOL(); err != nil {
+			return err
+		}
+	}
+}
+
+type tomlKey struct {
+	parts []string
+	line  int
+	col   int
+}
+
+func (k tomlKey) String() string {
+	return strings.Join(k.parts, NameSeparator)
+}
+
+func (p *tomlParser) parseKey() (*tomlKey, error) {
+	key := &tomlKey{
+		line: p.line,
+		col:  p.col,
+	}
+	for {
+		p.skipSpace()
+		part, err := p.parseKeyPart()
+		if err != nil {
+			return nil, err
+		}
+		key.parts = append(key.parts, part)
+		p.skipSpace()
+		if p.peek() != '.' {
+			return key, nil
+		}
+		p.next()
+	}
+}
+
+func isTOMLBareKeyChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' || c == '_' || c == '-'
+}
+
+func (p *tomlParser) parseKeyPart() (string, error) {
+	switch c := p.peek(); {
+	case c == '"':
+		return p.parseBasicString()
+	case c == '\'':
+		return p.parseLiteralString()
+	case isTOMLBareKeyChar(c):
+		start := p.pos
+		for isTOMLBareKeyChar(p.peek()) {
+			p.next()
+		}
+		return p.src[start:p.pos], nil
+	default:
+		return "", p.errorf("invalid key")
+	}
+}
+
+func (p *tomlParser) parseTableHeader() error {
+	isArray := p.hasPrefix("[[")
+	if isArray {
+		p.skip(2)
+	} else {
+		p.next()
+	}
+	key, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	closing := "]"
+	if isArray {
+		closing = "]]"
+	}
+	if !p.hasPrefix(closing) {
+		return p.errorf("expected %s", closing)
+	}
+	p.skip(len(closing))
+
+	parent := p.root
+	for _, part := range key.parts[:len(key.parts)-1] {
+		if parent, err = p.descend(parent, part, key); err != nil {
+			return err
+		}
+	}
+	last := key.parts[len(key.parts)-1]
+	v, exists := parent[last]
+
+	if isArray {
+		if !exists {
+			n := &DocumentNode{
+				Value:  []any{},
+				Line:   key.line,
+				Column: key.col,
+			}
+			p.arrayTables[n] = true
+			parent[last] = n
+			v = n
+		}
+		n := v.(*DocumentNode)
+		xs, ok := n.Value.([]any)
+		if !ok || !p.arrayTables[n] {
+			return p.errorAt(key.line, key.col, "%s is not an array of tables", key)
+		}
+		table := &DocumentNode{
+			Value:  Document{},
+			Line:   key.line,
+			Column: key.col,
+		}
+		n.Value = append(xs, table)
+		p.current = table.Value.(Document)
+		return nil
+	}
+
+	if !exists {
+		table := &DocumentNode{
+			Value:  Document{},
+			Line:   key.line,
+			Column: key.col,
+		}
+		p.defined[table] = true
+		parent[last] = table
+		p.current = table.Value.(Document)
+		return nil
+	}
+	n := v.(*DocumentNode)
+	d, ok := n.Value.(Document)
+	if !ok || p.defined[n] || p.inline[n] {
+		return p.errorAt(key.line, key.col, "duplicate key %s", key)
+	}
+	p.defined[n] = true
+	p.current = d
+	return nil
+}
+
+// descend returns the table of t by part, creates it if not exists.
+func (p *tomlParser) descend(t Document, part string, key *tomlKey) (Document, error) {
+	v, ok := t[part]
+	if !ok {
+		n := &DocumentNode{
+			Value:  Document{},
+			Line:   key.line,
+			Column: key.col,
+		}
+		t[part] = n
+		return n.Value.(Document), nil
+	}
+	n := v.(*DocumentNode)
+	if p.inline[n] {
+		return nil, p.errorAt(key.line, key.col, "%s: cannot extend %s", key, part)
+	}
+	switch x := n.Value.(type) {
+	case Document:
+		return x, nil
+	case []any:
+		if p.arrayTables[n] && len(x) > 0 {
+			return x[len(x)-1].(*DocumentNode).Value.(Document), nil
+		}
+	}
+	return nil, p.errorAt(key.line, key.col, "%s: %s is not a table", key, part)
+}
+
+func (p *tomlParser) parseKeyValue(t Document) error {
+	key, err := p.parseKey()
+	if err != nil {
+		return err
+	}
+	if p.peek() != '=' {
+		return p.errorf("expected =")
+	}
+	p.next()
+	p.skipSpace()
+	v, err := p.parseValue()
+	if err != nil {
+		return err
+	}
+
+	parent := t
+	for _, part := range key.parts[:len(key.parts)-1] {
+		if parent, err = p.descend(parent, part, key); err != nil {
+			return err
+		}
+	}
+	last := key.parts[len(key.parts)-1]
+	if _, exists := parent[last]; exists {
+		return p.errorAt(key.line, key.col, "duplicate key %s", key)
+	}
+	parent[last] = v
+	return nil
+}
+
+func (p *tomlParser) parseValue() (*DocumentNode, error) {
+	n := &DocumentNode{
+		Line:   p.line,
+		Column: p.col,
+	}
+	var err error
+	switch c := p.peek(); {
+	case p.hasPrefix(`"""`):
+		n.Value, err = p.parseMultilineBasicString()
+	case c == '"':
+		n.Value, err = p.parseBasicString()
+	case p.hasPrefix(`'''`):
+		n.Value, err = p.parseMultilineLiteralString()
+	case c == '\'':
+		n.Value, err = p.parseLiteralString()
+	case c == '[':
+		n.Value, err = p.parseArray()
+		p.inline[n] = true
+	case c == '{':
+		n.Value, err = p.parseInlineTable()
+		p.inline[n] = true
+	default:
+		n.Value, err = p.parseScalar()
+	}
+	if err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (p *tomlParser) parseArray() ([]any, error) {
+	p.next() // [
+	xs := []any{}
+	for {
+		p.skipBlank()
+		if p.eof() {
+			return nil, p.errorf("unterminated array")
+		}
+		if p.peek() == ']' {
+			p.next()
+			return xs, nil
+		}
+		v, err := p.parseValue()
+		if err != nil {
+			return nil, err
+		}
+		xs = append(xs, v)
+		p.skipBlank()
+		switch p.peek() {
+		case ',':
+			p.next()
+		case ']':
+			p.next()
+			return xs, nil
+		default:
+			return nil, p.errorf("expected , or ]")
+		}
+	}
+}
+
+func (p *tomlParser) parseInlineTable() (Document, error) {
+	p.next() // {
+	d := Document{}
+	p.skipSpace()
+	if p.peek() == '}' {
+		p.next()
+		return d, nil
+	}
+	for {
+		if err := p.parseKeyValue(d); err != nil {
+			return nil, err
+		}
+		p.skipSpace()
+		switch p.peek() {
+		case ',':
+			p.next()
+		case '}':
+			p.next()
+			return d, nil
+		default:
+			return nil, p.errorf("expected , or }")
+		}
+	}
+}
+
+func (p *tomlParser) parseBasicString() (string, error) {
+	p.next() // "
+	var b strings.Builder
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		switch c := p.peek(); c {
+		case '"':
+			p.next()
+			return b.String(), nil
+		case '\\':
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(p.next())
+		}
+	}
+}
+
+func (p *tomlParser) parseMultilineBasicString() (string, error) {
+	p.skip(3) // """
+	p.trimFirstNewline()
+	var b strings.Builder
+	for {
+		switch {
+		case p.eof():
+			return "", p.errorf("unterminated string")
+		case p.hasPrefix(`"""`):
+			return p.closeMultilineString(&b, '"'), nil
+		case p.isLineEndingBackslash():
+			p.next()
+			for c := p.peek(); c == ' ' || c == '\t' || c == '\n' || c == '\r'; c = p.peek() {
+				p.next()
+			}
+		case p.peek() == '\\':
+			if err := p.parseEscape(&b); err != nil {
+				return "", err
+			}
+		default:
+			b.WriteByte(p.next())
+		}
+	}
+}
+
+// isLineEndingBackslash reports true if a backslash followed by whitespaces and a newline comes next.
+func (p *tomlParser) isLineEndingBackslash() bool {
+	if p.peek() != '\\' {
+		return false
+	}
+	rest := strings.TrimLeft(p.src[p.pos+1:], " \t")
+	return strings.HasPrefix(rest, "\n") || strings.HasPrefix(rest, "\r\n")
+}
+
+func (p *tomlParser) parseLiteralString() (string, error) {
+	p.next() // '
+	start := p.pos
+	for {
+		if p.eof() || p.peek() == '\n' {
+			return "", p.errorf("unterminated string")
+		}
+		if p.peek() == '\'' {
+			s := p.src[start:p.pos]
+			p.next()
+			return s, nil
+		}
+		p.next()
+	}
+}
+
+func (p *tomlParser) parseMultilineLiteralString() (string, error) {
+	p.skip(3) // '''
+	p.trimFirstNewline()
+	var b strings.Builder
+	for {
+		switch {
+		case p.eof():
+			return "", p.errorf("unterminated string")
+		case p.hasPrefix(`'''`):
+			return p.closeMultilineString(&b, '\''), nil
+		default:
+			b.WriteByte(p.next())
+		}
+	}
+}
+
+func (p *tomlParser) trimFirstNewline() {
+	switch {
+	case p.hasPrefix("\n"):
+		p.next()
+	case p.hasPrefix("\r\n"):
+		p.skip(2)
+	}
+}
+
+// closeMultilineString consumes the closing delimiter.
+// Up to 2 quotes just before the delimiter are the content.
+func (p *tomlParser) closeMultilineString(b *strings.Builder, quote byte) string {
+	n := 0
+	for p.peek() == quote && n < 5 {
+		p.next()
+		n++
+	}
+	for range n - 3 {
+		b.WriteByte(quote)
+	}
+	return b.String()
+}
+
+func (p *tomlParser) parseEscape(b *strings.Builder) error {
+	line, col := p.line, p.col
+	p.next() // \
+	if p.eof() {
+		return p.errorAt(line, col, "invalid escape")
+	}
+	switch c := p.next(); c {
+	case 'b':
+		b.WriteByte('\b')
+	case 't':
+		b.WriteByte('\t')
+	case 'n':
+		b.WriteByte('\n')
+	case 'f':
+		b.WriteByte('\f')
+	case 'r':
+		b.WriteByte('\r')
+	case 'e':
+		b.WriteByte('\x1b')
+	case '"':
+		b.WriteByte('"')
+	case '\\':
+		b.WriteByte('\\')
+	case 'u', 'U':
+		size := 4
+		if c == 'U' {
+			size = 8
+		}
+		if p.pos+size > len(p.src) {
+			return p.errorAt(line, col, "invalid escape")
+		}
+		x, err := strconv.ParseUint(p.src[p.pos:p.pos+size], 16, 32)
+		if err != nil || !utf8.ValidRune(rune(x)) {
+			return p.errorAt(line, col, "invalid escape")
+		}
+		p.skip(size)
+		b.WriteRune(rune(x))
+	default:
+		return p.errorAt(line, col, "invalid escape")
+	}
+	return nil
+}
+
+var (
+	tomlDateRegexp     = regexp.MustCompile(`^\d{4}-\d{2}-\d{2}$`)
+	tomlDateTimeRegexp = regexp.MustCompile(`^(\d{4}-\d{2}-\d{2}([Tt ]\d{2}:\d{2}(:\d{2}(\.\d+)?)?([Zz]|[+-]\d{2}:\d{2})?)?|\d{2}:\d{2}(:\d{2}(\.\d+)?)?)$`)
+)
+
+func isTOMLScalarChar(c byte) bool {
+	return c >= 'A' && c <= 'Z' || c >= 'a' && c <= 'z' || c >= '0' && c <= '9' ||
+		c == '_' || c == '-' || c == '+' || c == '.' || c == ':'
+}
+
+// parseScalar parses a boolean, a number or a date-time.
+func (p *tomlParser) parseScalar() (any, error) {
+	line, col := p.line, p.col
+	start := p.pos
+	for isTOMLScalarChar(p.peek()) {
+		p.next()
+	}
+	token := p.src[start:p.pos]
+	// date and time separated by a space
+	if tomlDateRegexp.MatchString(token) && p.peek() == ' ' &&
+		p.pos+1 < len(p.src) && p.src[p.pos+1] >= '0' && p.src[p.pos+1] <= '9' {
+		p.next()
+		for isTOMLScalarChar(p.peek()) {
+			p.next()
+		}
+		token = p.src[start:p.pos]
+	}
+
+	switch token {
+	case "":
+		return nil, p.errorAt(line, col, "invalid value")
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "inf", "+inf":
+		return math.Inf(1), nil
+	case "-inf":
+		return math.Inf(-1), nil
+	case "nan", "+nan", "-nan":
+		return math.NaN(), nil
+	}
+
+	if tomlDateTimeRegexp.MatchString(token) {
+		// for time.RFC3339
+		if len(token) > 10 && token[10] == ' ' {
+			token = token[:10] + "T" + token[11:]
+		}
+		return token, nil
+	}
+
+	if strings.HasPrefix(token, "_") || strings.HasSuffix(token, "_") || strings.Contains(token, "__") {
+		return nil, p.errorAt(line, col, "invalid number %s", token)
+	}
+	digits := strings.ReplaceAll(token, "_", "")
+	for prefix, base := range map[string]int{"0x": 16, "0o": 8, "0b": 2} {
+		if s, ok := strings.CutPrefix(digits, prefix); ok {
+			x, err := strconv.ParseInt(s, base, 64)
+			if err != nil {
+				return nil, p.errorAt(line, col, "invalid number %s", token)
+			}
+			return x, nil
+		}
+	}
+	if strings.ContainsAny(digits, ".eE") {
+		x, err := strconv.ParseFloat(digits, 64)
+		if err != nil {
+			return nil, p.errorAt(line, col, "invalid number %s", token)
+		}
+		return x, nil
+	}
+	x, err := strconv.ParseInt(digits, 10, 64)
+	if err != nil {
+		return nil, p.errorAt(line, col, "invalid number %s", token)
+	}
+	return x, nil
+}
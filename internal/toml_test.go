@@ -0,0 +1,369 @@
+package internal_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+// plainDocument removes [*internal.DocumentNode] from v.
+func plainDocument(v any) any {
+	if n, ok := v.(*internal.DocumentNode); ok {
+		v = n.Value
+	}
+	switch v := v.(type) {
+	case internal.Document:
+		d := map[string]any{}
+		for k, x := range v {
+			d[k] = plainDocument(x)
+		}
+		return d
+	case []any:
+		xs := make([]any, len(v))
+		for i, x := range v {
+			xs[i] = plainDocument(x)
+		}
+		return xs
+	default:
+		return v
+	}
+}
+
+func TestDecodeTOML(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		input string
+		want  map[string]any
+	}{
+		{
+			title: "empty",
+			input: "",
+			want:  map[string]any{},
+		},
+		{
+			title: "comments",
+			input: `# comment
+a = 1 # comment
+
+# comment
+`,
+			want: map[string]any{"a": int64(1)},
+		},
+		{
+			title: "keys",
+			input: `bare_key-1 = 1
+"quoted key" = 2
+'literal key' = 3
+a.b . c = 4
+"a"."d" = 5
+`,
+			want: map[string]any{
+				"bare_key-1":  int64(1),
+				"quoted key":  int64(2),
+				"literal key": int64(3),
+				"a": map[string]any{
+					"b": map[string]any{"c": int64(4)},
+					"d": int64(5),
+				},
+			},
+		},
+		{
+			title: "strings",
+			input: `basic = "a\tb\"c\u00e9"
+literal = 'C:\path'
+multi = """
+line1
+line2\
+    continued"""
+multi_literal = '''
+raw\n'''
+quotes = """a""""
+`,
+			want: map[string]any{
+				"basic":         "a\tb\"cé",
+				"literal":       `C:\path`,
+				"multi":         "line1\nline2continued",
+				"multi_literal": `raw\n`,
+				"quotes":        `a"`,
+			},
+		},
+		{
+			title: "numbers",
+			input: `int = +1_000
+neg = -1
+hex = 0xff
+oct = 0o17
+bin = 0b101
+float = 1.5
+exp = 5e+2
+inf = -inf
+`,
+			want: map[string]any{
+				"int":   int64(1000),
+				"neg":   int64(-1),
+				"hex":   int64(255),
+				"oct":   int64(15),
+				"bin":   int64(5),
+				"float": 1.5,
+				"exp":   500.0,
+				"inf":   math.Inf(-1),
+			},
+		},
+		{
+			title: "booleans and datetimes",
+			input: `t = true
+f = false
+odt = 1979-05-27T07:32:00Z
+space = 1979-05-27 07:32:00-07:00
+date = 1979-05-27
+time = 07:32:00
+`,
+			want: map[string]any{
+				"t":     true,
+				"f":     false,
+				"odt":   "1979-05-27T07:32:00Z",
+				"space": "1979-05-27T07:32:00-07:00",
+				"date":  "1979-05-27",
+				"time":  "07:32:00",
+			},
+		},
+		{
+			title: "arrays",
+			input: `a = [1, 2]
+b = [
+  "x", # comment
+  "y",
+]
+c = [[1], []]
+`,
+			want: map[string]any{
+				"a": []any{int64(1), int64(2)},
+				"b": []any{"x", "y"},
+				"c": []any{[]any{int64(1)}, []any{}},
+			},
+		},
+		{
+			title: "tables",
+			input: `a = 1
+[db]
+host = "localhost"
+[db.pool]
+size = 10
+[inline]
+point = { x = 1, y.z = 2 }
+empty = {}
+`,
+			want: map[string]any{
+				"a": int64(1),
+				"db": map[string]any{
+					"host": "localhost",
+					"pool": map[string]any{"size": int64(10)},
+				},
+				"inline": map[string]any{
+					"point": map[string]any{
+						"x": int64(1),
+						"y": map[string]any{"z": int64(2)},
+					},
+					"empty": map[string]any{},
+				},
+			},
+		},
+		{
+			title: "array of tables",
+			input: `[[servers]]
+name = "a"
+[servers.meta]
+id = 1
+[[servers]]
+name = "b"
+`,
+			want: map[string]any{
+				"servers": []any{
+					map[string]any{
+						"name": "a",
+						"meta": map[string]any{"id": int64(1)},
+					},
+					map[string]any{"name": "b"},
+				},
+			},
+		},
+		{
+			title: "implicit table defined later",
+			input: `[a.b]
+c = 1
+[a]
+d = 2
+`,
+			want: map[string]any{
+				"a": map[string]any{
+					"b": map[string]any{"c": int64(1)},
+					"d": int64(2),
+				},
+			},
+		},
+		{
+			title: "crlf",
+			input: "a = 1\r\nb = 'x'\r\n",
+			want: map[string]any{
+				"a": int64(1),
+				"b": "x",
+			},
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			got, err := internal.DecodeTOML(strings.NewReader(tc.input))
+			assert.Nil(t, err)
+			assert.Equal(t, tc.want, plainDocument(got))
+		})
+	}
+}
+
+func TestDecodeTOMLError(t *testing.T) {
+	for _, tc := range []struct {
+		title string
+		input string
+		want  string
+	}{
+		{
+			title: "no value",
+			input: "a =\n",
+			want:  "line 1, column 4: invalid value",
+		},
+		{
+			title: "duplicate key",
+			input: "a = 1\na = 2\n",
+			want:  "line 2, column 1: duplicate key a",
+		},
+		{
+			title: "duplicate table",
+			input: "[a]\n[a]\n",
+			want:  "line 2, column 2: duplicate key a",
+		},
+		{
+			title: "extend inline table",
+			input: "a = {}\n[a.b]\n",
+			want:  "line 2, column 2: a.b: cannot extend a",
+		},
+		{
+			title: "not a table",
+			input: "a = 1\n[a.b]\n",
+			want:  "line 2, column 2: a.b: a is not a table",
+		},
+		{
+			title: "unterminated string",
+			input: "a = \"x\nb = 1\n",
+			want:  "line 1, column 7: unterminated string",
+		},
+		{
+			title: "unterminated array",
+			input: "a = [1,",
+			want:  "line 1, column 8: unterminated array",
+		},
+		{
+			title: "invalid number",
+			input: "a = 1__0\n",
+			want:  "line 1, column 5: invalid number 1__0",
+		},
+		{
+			title: "invalid escape",
+			input: `a = "\x"`,
+			want:  "line 1, column 6: invalid escape",
+		},
+		{
+			title: "trailing garbage",
+			input: "a = 1 b\n",
+			want:  "line 1, column 7: unexpected 'b'",
+		},
+	} {
+		t.Run(tc.title, func(t *testing.T) {
+			_, err := internal.DecodeTOML(strings.NewReader(tc.input))
+			assert.ErrorIs(t, err, internal.ErrStructConfig)
+			assert.ErrorContains(t, err, tc.want)
+		})
+	}
+}
+
+func TestTOMLDocumentReceptor(t *testing.T) {
+	type DB struct {
+		Host string `name:"host"`
+		Port int    `name:"port" default:"5432"`
+	}
+	type T struct {
+		B     bool              `name:"b"`
+		I     int               `name:"i"`
+		F     float64           `name:"f"`
+		S     string            `name:"s" default:"str"`
+		Slice []int             `name:"slice"`
+		Map   map[string]string `name:"labels"`
+		D     time.Duration     `name:"d"`
+		Since time.Time         `name:"since"`
+		DB    DB                `name:"db"`
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		const input = `b = true
+i = 1
+f = 1.5
+slice = [1, 2]
+d = "1m"
+since = 2024-01-02T03:04:05Z
+
+[labels]
+env = "dev"
+
+[db]
+host = "localhost"
+`
+		want := T{
+			B:     true,
+			I:     1,
+			F:     1.5,
+			S:     "str",
+			Slice: []int{1, 2},
+			Map:   map[string]string{"env": "dev"},
+			D:     time.Minute,
+			Since: time.Date(2024, 1, 2, 3, 4, 5, 0, time.UTC),
+			DB: DB{
+				Host: "localhost",
+				Port: 5432,
+			},
+		}
+
+		doc, err := internal.DecodeTOML(strings.NewReader(input))
+		assert.Nil(t, err)
+
+		var got T
+		r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+		assert.Nil(t, err)
+
+		typ, err := internal.NewType(got, "")
+		assert.Nil(t, err)
+
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		const input = `[db]
+host = "localhost"
+port = "x"
+`
+		doc, err := internal.DecodeTOML(strings.NewReader(input))
+		assert.Nil(t, err)
+
+		var got T
+		r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+		assert.Nil(t, err)
+
+		typ, err := internal.NewType(got, "")
+		assert.Nil(t, err)
+
+		err = typ.Accept(r)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorContains(t, err, "line 3, column 8: DB.Port (db.port): ")
+	})
+}
@@ -1,11 +1,15 @@
 package internal
 
-import "reflect"
+import (
+	"reflect"
+	"slices"
+)
 
 // Type is the metadata of struct.
 type Type struct {
 	typ    reflect.Type // Struct
 	prefix string
+	fields []StructField
 }
 
 // NewType constructs [Type] from struct value.
@@ -15,24 +19,183 @@ func NewType(v any, prefix string) (*Type, error) {
 	if x := t.Kind(); x != reflect.Struct {
 		return nil, JoinErrors(ErrNotStruct, Errorf("cannot accept type %s", t.Name()))
 	}
-	return &Type{
+	typ := &Type{
 		typ:    t,
 		prefix: prefix,
-	}, nil
+	}
+	fields, err := typ.collectFields(t, "", "", nil)
+	if err != nil {
+		return nil, err
+	}
+	typ.fields = fields
+	return typ, nil
+}
+
+// collectFields returns the metadata of the fields of t.
+//
+// A struct field that has a name tag and has fields with name tags is a nested struct:
+// the fields of it are collected instead of itself, and their names are joined with the name of it.
+//
+// The fields of an embedded struct without a name tag are promoted like Go does:
+// a shallower field hides deeper fields with the same name,
+// and fields with the same name at the same depth are ambiguous.
+// Ambiguous fields are errors if some of them have name tags, otherwise they are dropped.
+func (t Type) collectFields(typ reflect.Type, goPath, namespace string, index []int) ([]StructField, error) {
+	xs, err := t.collectPromotedFields(typ, goPath, namespace, index, 0)
+	if err != nil {
+		return nil, err
+	}
+	fields, err := t.resolvePromotion(xs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, x := range []struct {
+		title string
+		get   func(*Tag) (string, bool)
+	}{
+		{title: "name", get: (*Tag).Name},
+		{title: "flag", get: (*Tag).Flag},
+		{title: "key", get: (*Tag).Key},
+	} {
+		names := map[string]string{} // tag value to field name
+		for _, f := range fields {
+			name, ok := x.get(f.Tag())
+			if !ok {
+				continue
+			}
+			if other, found := names[name]; found {
+				return nil, Errorf("%s: ambiguous %s %s: %s and %s", t.Name(), x.title, name, other, f.Name())
+			}
+			names[name] = f.Name()
+		}
+	}
+	return fields, nil
+}
+
+type promotedField struct {
+	field StructField
+	depth int // the number of embedded structs to reach the field
+}
+
+func (t Type) collectPromotedFields(
+	typ reflect.Type, goPath, namespace string, index []int, depth int,
+) ([]*promotedField, error) {
+	xs := []*promotedField{}
+	for i := range typ.NumField() {
+		x := typ.Field(i)
+		tag := NewTag(x.Tag, t.prefix).WithNamespace(namespace)
+		fieldIndex := append(append([]int{}, index...), i)
+		fieldName := x.Name
+		if goPath != "" {
+			fieldName = goPath + NameSeparator + x.Name
+		}
+
+		name, hasName := tag.Name()
+		switch {
+		case hasName && t.isNested(x.Type):
+			fields, err := t.collectFields(x.Type, fieldName, name, fieldIndex)
+			if err != nil {
+				return nil, err
+			}
+			for _, f := range fields {
+				xs = append(xs, &promotedField{
+					field: f,
+					depth: depth,
+				})
+			}
+		case !hasName && x.Anonymous && x.Type.Kind() == reflect.Struct:
+			fields, err := t.collectPromotedFields(x.Type, goPath, namespace, fieldIndex, depth+1)
+			if err != nil {
+				return nil, err
+			}
+			xs = append(xs, fields...)
+		default:
+			xs = append(xs, &promotedField{
+				field: NewStructField(
+					fieldName,
+					fieldKind(x.Type),
+					x.Type,
+					tag,
+					fieldIndex,
+				),
+				depth: depth,
+			})
+		}
+	}
+	return xs, nil
+}
+
+// resolvePromotion removes the fields hidden by shallower fields with the same name.
+// Fields with the same name at the same depth are ambiguous:
+// they are errors if some of them have name tags, otherwise they are dropped like Go does.
+func (t Type) resolvePromotion(xs []*promotedField) ([]StructField, error) {
+	shallowest := map[string][]*promotedField{}
+	for _, x := range xs {
+		name := x.field.Name()
+		ys, ok := shallowest[name]
+		switch {
+		case !ok || x.depth < ys[0].depth:
+			shallowest[name] = []*promotedField{x}
+		case x.depth == ys[0].depth:
+			shallowest[name] = append(ys, x)
+		}
+	}
+
+	r := []StructField{}
+	for _, x := range xs {
+		ys := shallowest[x.field.Name()]
+		if ys[0] != x {
+			continue
+		}
+		if len(ys) > 1 {
+			if slices.ContainsFunc(ys, func(y *promotedField) bool {
+				_, ok := y.field.Tag().Name()
+				return ok
+			}) {
+				return nil, Errorf("%s: ambiguous field %s", t.Name(), x.field.Name())
+			}
+			continue
+		}
+		r = append(r, x.field)
+	}
+	return r, nil
+}
+
+// fieldKind returns the kind of typ, or the kind of the element if typ is a pointer to a supported kind.
+func fieldKind(typ reflect.Type) reflect.Kind {
+	if typ.Kind() == reflect.Pointer && IsSupportedKind(typ.Elem().Kind()) {
+		return typ.Elem().Kind()
+	}
+	return typ.Kind()
+}
+
+// isNested reports true if typ is a struct that has fields with name tags,
+// including the fields promoted from embedded structs.
+func (t Type) isNested(typ reflect.Type) bool {
+	if typ.Kind() != reflect.Struct {
+		return false
+	}
+	for x := range typ.Fields() {
+		if _, ok := NewTag(x.Tag, t.prefix).Name(); ok {
+			return true
+		}
+		if x.Anonymous && t.isNested(x.Type) {
+			return true
+		}
+	}
+	return false
 }
 
 // Fields returns the metadata of all fields of the struct.
+//
+// Fields of nested structs are included instead of the nested structs themselves,
+// and fields of embedded structs are promoted.
+// [StructField.Name] of them is the path of the Go field names joined by [NameSeparator], e.g. "DB.Host",
+// and [StructField.Index] is the index sequence for [reflect.Value.FieldByIndex].
+// [StructField.Kind] of a pointer to a supported kind is the kind of the element.
 func (t Type) Fields() []StructField {
-	xs := make([]StructField, t.typ.NumField())
-	for i := range t.typ.NumField() {
-		x := t.typ.Field(i)
-		xs[i] = NewStructField(
-			x.Name,
-			x.Type.Kind(),
-			NewTag(x.Tag, t.prefix),
-		)
-	}
-	return xs
+	return t.fields
 }
 
 // Name returns the name of the struct.
@@ -0,0 +1,180 @@
+package internal_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestType(t *testing.T) {
+	type Pool struct {
+		Size int `name:"size" default:"10"`
+	}
+	type DB struct {
+		Host string `name:"host" default:"localhost"`
+		Pool Pool   `name:"pool"`
+		Skip int
+	}
+	type Opaque struct {
+		S string
+	}
+	type T struct {
+		DB     DB     `name:"db"`
+		Opaque Opaque `name:"opaque"`
+		NoName DB
+		I      int `name:"i"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	type field struct {
+		name    string
+		kind    reflect.Kind
+		tagName string
+		index   []int
+	}
+	got := []field{}
+	for _, f := range typ.Fields() {
+		name, _ := f.Tag().Name()
+		got = append(got, field{
+			name:    f.Name(),
+			kind:    f.Kind(),
+			tagName: name,
+			index:   f.Index(),
+		})
+	}
+	assert.Equal(t, []field{
+		{name: "DB.Host", kind: reflect.String, tagName: "db.host", index: []int{0, 0}},
+		{name: "DB.Pool.Size", kind: reflect.Int, tagName: "db.pool.size", index: []int{0, 1, 0}},
+		{name: "DB.Skip", kind: reflect.Int, index: []int{0, 2}},
+		{name: "Opaque", kind: reflect.Struct, tagName: "opaque", index: []int{1}},
+		{name: "NoName", kind: reflect.Struct, index: []int{2}},
+		{name: "I", kind: reflect.Int, tagName: "i", index: []int{3}},
+	}, got)
+}
+
+func TestTypeEmbedded(t *testing.T) {
+	type Log struct {
+		Level string `name:"log_level" default:"info"`
+	}
+	type Shadow struct {
+		Level int `name:"shadow_level"`
+	}
+	type Nested struct {
+		Log
+		Port int `name:"port"`
+	}
+
+	t.Run("promote", func(t *testing.T) {
+		type T struct {
+			Log
+			Nested Nested `name:"nested"`
+			I      int    `name:"i"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		if !assert.Nil(t, err) {
+			return
+		}
+		type field struct {
+			name    string
+			tagName string
+			index   []int
+		}
+		got := []field{}
+		for _, f := range typ.Fields() {
+			name, _ := f.Tag().Name()
+			got = append(got, field{
+				name:    f.Name(),
+				tagName: name,
+				index:   f.Index(),
+			})
+		}
+		assert.Equal(t, []field{
+			{name: "Level", tagName: "log_level", index: []int{0, 0}},
+			{name: "Nested.Level", tagName: "nested.log_level", index: []int{1, 0, 0}},
+			{name: "Nested.Port", tagName: "nested.port", index: []int{1, 1}},
+			{name: "I", tagName: "i", index: []int{2}},
+		}, got)
+	})
+
+	t.Run("shallower field hides", func(t *testing.T) {
+		type T struct {
+			Log
+			Level string `name:"level"`
+		}
+		typ, err := internal.NewType(T{}, "")
+		if !assert.Nil(t, err) {
+			return
+		}
+		fields := typ.Fields()
+		if !assert.Equal(t, 1, len(fields)) {
+			return
+		}
+		name, _ := fields[0].Tag().Name()
+		assert.Equal(t, "level", name)
+		assert.Equal(t, []int{1}, fields[0].Index())
+	})
+
+	t.Run("ambiguous field", func(t *testing.T) {
+		type T struct {
+			Log
+			Shadow
+		}
+		_, err := internal.NewType(T{}, "")
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+	})
+
+	t.Run("ambiguous untagged field is dropped", func(t *testing.T) {
+		type A struct {
+			ID   int
+			Host string `name:"host"`
+		}
+		type B struct {
+			ID   int
+			Port int `name:"port"`
+		}
+		type T struct {
+			A
+			B
+		}
+		typ, err := internal.NewType(T{}, "")
+		if !assert.Nil(t, err) {
+			return
+		}
+		got := []string{}
+		for _, f := range typ.Fields() {
+			got = append(got, f.Name())
+		}
+		assert.Equal(t, []string{"Host", "Port"}, got)
+	})
+
+	t.Run("ambiguous name", func(t *testing.T) {
+		type T struct {
+			Log
+			LogLevel string `name:"log_level"`
+		}
+		_, err := internal.NewType(T{}, "")
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+	})
+
+	t.Run("ambiguous flag", func(t *testing.T) {
+		type T struct {
+			A int `name:"a" flag:"x"`
+			B int `name:"b" flag:"x"`
+		}
+		_, err := internal.NewType(T{}, "")
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+	})
+
+	t.Run("ambiguous key", func(t *testing.T) {
+		type T struct {
+			A int `name:"a" key:"b"`
+			B int `name:"b"`
+		}
+		_, err := internal.NewType(T{}, "")
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+	})
+}
@@ -58,6 +58,21 @@ func IsSupportedKind(k reflect.Kind) bool {
 	}
 }
 
+// IsOptional reports true if t is a pointer to a supported kind or [time.Time].
+// A nil value of it means that the value is not given.
+func IsOptional(t reflect.Type) bool {
+	return t != nil && t.Kind() == reflect.Pointer &&
+		(IsSupportedKind(t.Elem().Kind()) || t.Elem() == timeType)
+}
+
+// indirectType returns the element type of t if t is a pointer other than *[time.Location], otherwise t.
+func indirectType(t reflect.Type) reflect.Type {
+	if t.Kind() == reflect.Pointer && t != locationType {
+		return t.Elem()
+	}
+	return t
+}
+
 type Unsigned interface {
 	~uint | ~uint8 | ~uint16 | ~uint32 | ~uint64
 }
@@ -0,0 +1,292 @@
+package internal
+
+import (
+	"cmp"
+	"encoding"
+	"fmt"
+	"reflect"
+	"regexp"
+	"slices"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+)
+
+// Violation is a field that does not satisfy the validation tags.
+type Violation struct {
+	// Field is the description of the field, e.g. "DB.Port (flag --db.port, env DB_PORT)".
+	Field string
+	// Source is the source that supplied the value, e.g. "env DB_PORT", empty if unknown.
+	Source  string
+	Message string
+}
+
+func (v Violation) String() string {
+	if v.Source != "" {
+		return v.Field + " from " + v.Source + ": " + v.Message
+	}
+	return v.Field + ": " + v.Message
+}
+
+// ValidationError is the aggregated violations of the validation tags.
+type ValidationError struct {
+	Violations []*Violation
+}
+
+func (e *ValidationError) Error() string {
+	xs := make([]string, len(e.Violations))
+	for i, v := range e.Violations {
+		xs[i] = v.String()
+	}
+	return fmt.Sprintf("%s: %s: %s", ErrStructConfig, ErrValidation, strings.Join(xs, "; "))
+}
+
+func (e *ValidationError) Unwrap() []error {
+	return []error{ErrStructConfig, ErrValidation}
+}
+
+var _ Receptor = &ValidationReceptor{}
+
+// ValidationReceptor validates the field values by the validation tags:
+//   - min, max: the bounds of numbers and [time.Duration]
+//   - oneof: the allowed values separated by [OneOfSeparator]
+//   - pattern: the regular expression that strings should match
+//   - minlen, maxlen: the bounds of the length of strings, slices and maps
+//
+// The methods return errors for the invalid tag values.
+// The violations are collected and reported by [ValidationReceptor.Err].
+// The values of the fields with the secret tag are masked in the violations, see [RedactValue].
+type ValidationReceptor struct {
+	v          reflect.Value
+	envPrefix  string
+	source     func(StructField) string
+	violations []*Violation
+}
+
+// NewValidationReceptor returns a new [ValidationReceptor].
+// envPrefix is for the environment variable names in the violations, see [FieldEnvVar].
+// source returns the source that supplied the value of the field for [Violation.Source], can be nil.
+//
+// ptr should be a pointer of struct.
+func NewValidationReceptor(ptr any, envPrefix string, source func(StructField) string) (*ValidationReceptor, error) {
+	v := reflect.ValueOf(ptr)
+	if v.Kind() != reflect.Pointer || v.Elem().Kind() != reflect.Struct {
+		return nil, JoinErrors(ErrNotStructPointer)
+	}
+	return &ValidationReceptor{
+		v:         v.Elem(),
+		envPrefix: envPrefix,
+		source:    source,
+	}, nil
+}
+
+// Err returns [*ValidationError] if there are violations.
+func (r *ValidationReceptor) Err() error {
+	if len(r.violations) == 0 {
+		return nil
+	}
+	return &ValidationError{
+		Violations: r.violations,
+	}
+}
+
+func (r *ValidationReceptor) violate(s StructField, format string, v ...any) {
+	x := &Violation{
+		Field:   describeField(s, r.envPrefix),
+		Message: fmt.Sprintf(format, v...),
+	}
+	if r.source != nil {
+		x.Source = r.source(s)
+	}
+	r.violations = append(r.violations, x)
+}
+
+// value returns the value of the field, the element if the field is a pointer.
+// ok is false if the field is a nil pointer.
+func (r *ValidationReceptor) value(s StructField) (reflect.Value, bool) {
+	v := r.v.FieldByIndex(s.Index())
+	if v.Kind() == reflect.Pointer && v.Type() != locationType {
+		if v.IsNil() {
+			return v, false
+		}
+		v = v.Elem()
+	}
+	return v, true
+}
+
+func (r *ValidationReceptor) Bool(s StructField) error {
+	v, ok := r.value(s)
+	if !ok {
+		return nil
+	}
+	r.oneOf(s, strconv.FormatBool(v.Bool()))
+	return nil
+}
+
+func (r *ValidationReceptor) Int(s StructField) error   { return r.int(s) }
+func (r *ValidationReceptor) Int8(s StructField) error  { return r.int(s) }
+func (r *ValidationReceptor) Int16(s StructField) error { return r.int(s) }
+func (r *ValidationReceptor) Int32(s StructField) error { return r.int(s) }
+func (r *ValidationReceptor) Int64(s StructField) error { return r.int(s) }
+
+func (r *ValidationReceptor) int(s StructField) error {
+	v, ok := r.value(s)
+	if !ok {
+		return nil
+	}
+	x := v.Int()
+	r.oneOf(s, strconv.FormatInt(x, 10))
+	return validateRange(r, s, x, func(t string) (int64, error) { return strconv.ParseInt(t, 10, 64) })
+}
+
+func (r *ValidationReceptor) Uint(s StructField) error   { return r.uint(s) }
+func (r *ValidationReceptor) Uint8(s StructField) error  { return r.uint(s) }
+func (r *ValidationReceptor) Uint16(s StructField) error { return r.uint(s) }
+func (r *ValidationReceptor) Uint32(s StructField) error { return r.uint(s) }
+func (r *ValidationReceptor) Uint64(s StructField) error { return r.uint(s) }
+
+func (r *ValidationReceptor) uint(s StructField) error {
+	v, ok := r.value(s)
+	if !ok {
+		return nil
+	}
+	x := v.Uint()
+	r.oneOf(s, strconv.FormatUint(x, 10))
+	return validateRange(r, s, x, func(t string) (uint64, error) { return strconv.ParseUint(t, 10, 64) })
+}
+
+func (r *ValidationReceptor) Float32(s StructField) error { return r.float(s) }
+func (r *ValidationReceptor) Float64(s StructField) error { return r.float(s) }
+
+func (r *ValidationReceptor) float(s StructField) error {
+	v, ok := r.value(s)
+	if !ok {
+		return nil
+	}
+	x := v.Float()
+	r.oneOf(s, strconv.FormatFloat(x, 'f', -1, 64))
+	return validateRange(r, s, x, func(t string) (float64, error) { return strconv.ParseFloat(t, 64) })
+}
+
+func (r *ValidationReceptor) String(s StructField) error {
+	v, ok := r.value(s)
+	if !ok {
+		return nil
+	}
+	return r.string(s, v.String())
+}
+
+func (r *ValidationReceptor) string(s StructField, x string) error {
+	r.oneOf(s, x)
+	if err := r.length(s, utf8.RuneCountInString(x)); err != nil {
+		return err
+	}
+	if p, ok := s.Tag().Pattern(); ok {
+		re, err := compilePattern(p)
+		if err != nil {
+			return Errorf("%s: invalid %s tag value %s: %v", s.Name(), TagPattern, p, err)
+		}
+		if !re.MatchString(x) {
+			r.violate(s, "%q does not match %s", RedactValue(s, x), p)
+		}
+	}
+	return nil
+}
+
+// Any validates [time.Duration] by min and max, the length of slices and maps,
+// and the string of [encoding.TextMarshaler] and [fmt.Stringer] like strings.
+func (r *ValidationReceptor) Any(s StructField) error {
+	v, ok := r.value(s)
+	if !ok {
+		return nil
+	}
+	switch {
+	case v.Type() == durationType:
+		x := time.Duration(v.Int())
+		r.oneOf(s, x.String())
+		return validateRange(r, s, x, time.ParseDuration)
+	case v.Kind() == reflect.Slice, v.Kind() == reflect.Map:
+		return r.length(s, v.Len())
+	}
+	if !v.CanInterface() {
+		return nil
+	}
+	switch x := v.Interface().(type) {
+	case encoding.TextMarshaler:
+		b, err := x.MarshalText()
+		if err != nil {
+			return err
+		}
+		return r.string(s, string(b))
+	case fmt.Stringer:
+		return r.string(s, x.String())
+	}
+	return nil
+}
+
+// patterns is the cache of the compiled pattern tag values.
+var patterns sync.Map // string -> *regexp.Regexp
+
+func compilePattern(p string) (*regexp.Regexp, error) {
+	if re, ok := patterns.Load(p); ok {
+		return re.(*regexp.Regexp), nil
+	}
+	re, err := regexp.Compile(p)
+	if err != nil {
+		return nil, err
+	}
+	patterns.Store(p, re)
+	return re, nil
+}
+
+func (r *ValidationReceptor) oneOf(s StructField, x string) {
+	if xs, ok := s.Tag().OneOf(); ok && !slices.Contains(xs, x) {
+		r.violate(s, "%s is not one of %s", RedactValue(s, x), strings.Join(xs, OneOfSeparator))
+	}
+}
+
+func (r *ValidationReceptor) length(s StructField, n int) error {
+	if t, ok := s.Tag().MinLen(); ok {
+		m, err := strconv.Atoi(t)
+		if err != nil {
+			return Errorf("%s: invalid %s tag value %s", s.Name(), TagMinLen, t)
+		}
+		if n < m {
+			r.violate(s, "length %d is less than %s %d", n, TagMinLen, m)
+		}
+	}
+	if t, ok := s.Tag().MaxLen(); ok {
+		m, err := strconv.Atoi(t)
+		if err != nil {
+			return Errorf("%s: invalid %s tag value %s", s.Name(), TagMaxLen, t)
+		}
+		if n > m {
+			r.violate(s, "length %d is greater than %s %d", n, TagMaxLen, m)
+		}
+	}
+	return nil
+}
+
+func validateRange[T cmp.Ordered](r *ValidationReceptor, s StructField, x T, parse func(string) (T, error)) error {
+	if t, ok := s.Tag().Min(); ok {
+		m, err := parse(t)
+		if err != nil {
+			return Errorf("%s: invalid %s tag value %s", s.Name(), TagMin, t)
+		}
+		if x < m {
+			r.violate(s, "%s is less than %s %v", RedactValue(s, fmt.Sprint(x)), TagMin, m)
+		}
+	}
+	if t, ok := s.Tag().Max(); ok {
+		m, err := parse(t)
+		if err != nil {
+			return Errorf("%s: invalid %s tag value %s", s.Name(), TagMax, t)
+		}
+		if x > m {
+			r.violate(s, "%s is greater than %s %v", RedactValue(s, fmt.Sprint(x)), TagMax, m)
+		}
+	}
+	return nil
+}
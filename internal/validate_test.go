@@ -0,0 +1,144 @@
+package internal_test
+
+import (
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValidationReceptor(t *testing.T) {
+	type DB struct {
+		Port uint16 `name:"port" min:"1"`
+	}
+	type T struct {
+		B       bool              `name:"b" oneof:"true"`
+		I       int               `name:"i" min:"-1" max:"10"`
+		F       float64           `name:"f" min:"0.5"`
+		Level   string            `name:"level" oneof:"debug|info|warn"`
+		ID      string            `name:"id" pattern:"^[a-z]+$" minlen:"2" maxlen:"4"`
+		P       *int              `name:"p" max:"1"`
+		D       time.Duration     `name:"d" min:"1s" max:"1m"`
+		Hosts   []string          `name:"hosts" minlen:"1"`
+		Labels  map[string]string `name:"labels" maxlen:"1"`
+		LogLvl  slog.Level        `name:"log_level" oneof:"INFO|WARN"`
+		NoCheck int               `name:"no_check"`
+		DB      DB                `name:"db"`
+	}
+
+	typ, err := internal.NewType(T{}, "")
+	assert.Nil(t, err)
+
+	t.Run("ok", func(t *testing.T) {
+		v := T{
+			B:      true,
+			I:      -1,
+			F:      0.5,
+			Level:  "info",
+			ID:     "abcd",
+			D:      time.Minute,
+			Hosts:  []string{"a"},
+			LogLvl: slog.LevelWarn,
+			DB: DB{
+				Port: 1,
+			},
+		}
+		r, err := internal.NewValidationReceptor(&v, "", nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+		assert.Nil(t, r.Err())
+	})
+
+	t.Run("violations", func(t *testing.T) {
+		v := T{
+			I:      11,
+			F:      0.1,
+			Level:  "trace",
+			ID:     "A",
+			P:      new(2),
+			D:      time.Millisecond,
+			Labels: map[string]string{"a": "1", "b": "2"},
+			LogLvl: slog.LevelDebug,
+		}
+		r, err := internal.NewValidationReceptor(&v, "app_", nil)
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+
+		err = r.Err()
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorIs(t, err, internal.ErrValidation)
+
+		var verr *internal.ValidationError
+		if !assert.ErrorAs(t, err, &verr) {
+			return
+		}
+		got := make([]string, len(verr.Violations))
+		for i, x := range verr.Violations {
+			got[i] = x.String()
+		}
+		assert.Equal(t, []string{
+			"B (flag --b, env APP_B): false is not one of true",
+			"I (flag --i, env APP_I): 11 is greater than max 10",
+			"F (flag --f, env APP_F): 0.1 is less than min 0.5",
+			"Level (flag --level, env APP_LEVEL): trace is not one of debug|info|warn",
+			"ID (flag --id, env APP_ID): length 1 is less than minlen 2",
+			`ID (flag --id, env APP_ID): "A" does not match ^[a-z]+$`,
+			"P (flag --p, env APP_P): 2 is greater than max 1",
+			"D (flag --d, env APP_D): 1ms is less than min 1s",
+			"Hosts (flag --hosts, env APP_HOSTS): length 0 is less than minlen 1",
+			"Labels (flag --labels, env APP_LABELS): length 2 is greater than maxlen 1",
+			"LogLvl (flag --log_level, env APP_LOG_LEVEL): DEBUG is not one of INFO|WARN",
+			"DB.Port (flag --db.port, env APP_DB_PORT): 0 is less than min 1",
+		}, got)
+	})
+
+	t.Run("source", func(t *testing.T) {
+		v := T{
+			B:     true,
+			F:     0.5,
+			Level: "trace",
+			ID:    "ab",
+			D:     time.Second,
+			Hosts: []string{"a"},
+			DB: DB{
+				Port: 1,
+			},
+		}
+		r, err := internal.NewValidationReceptor(&v, "", func(f internal.StructField) string {
+			if f.Name() == "Level" {
+				return "env LEVEL"
+			}
+			return ""
+		})
+		assert.Nil(t, err)
+		assert.Nil(t, typ.Accept(r))
+
+		var verr *internal.ValidationError
+		if !assert.ErrorAs(t, r.Err(), &verr) {
+			return
+		}
+		got := make([]string, len(verr.Violations))
+		for i, x := range verr.Violations {
+			got[i] = x.String()
+		}
+		assert.Equal(t, []string{
+			"Level (flag --level, env LEVEL) from env LEVEL: trace is not one of debug|info|warn",
+		}, got)
+	})
+
+	t.Run("invalid tag", func(t *testing.T) {
+		type T struct {
+			I int    `name:"i" min:"x"`
+			S string `name:"s" pattern:"("`
+		}
+		typ, err := internal.NewType(T{}, "")
+		assert.Nil(t, err)
+
+		var v T
+		r, err := internal.NewValidationReceptor(&v, "", nil)
+		assert.Nil(t, err)
+		assert.ErrorIs(t, typ.Accept(r), internal.ErrStructConfig)
+	})
+}
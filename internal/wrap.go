@@ -0,0 +1,40 @@
+package internal
+
+var _ Receptor = &ErrorWrapReceptor{}
+
+// ErrorWrapReceptor wraps the errors of [Receptor].
+type ErrorWrapReceptor struct {
+	r    Receptor
+	wrap func(StructField, error) error
+}
+
+// NewErrorWrapReceptor returns a [Receptor] that calls r and wraps the error by wrap.
+func NewErrorWrapReceptor(r Receptor, wrap func(StructField, error) error) *ErrorWrapReceptor {
+	return &ErrorWrapReceptor{
+		r:    r,
+		wrap: wrap,
+	}
+}
+
+func (r ErrorWrapReceptor) try(f func(StructField) error, s StructField) error {
+	if err := f(s); err != nil {
+		return r.wrap(s, err)
+	}
+	return nil
+}
+
+func (r ErrorWrapReceptor) Bool(f StructField) error    { return r.try(r.r.Bool, f) }
+func (r ErrorWrapReceptor) Int(f StructField) error     { return r.try(r.r.Int, f) }
+func (r ErrorWrapReceptor) Int8(f StructField) error    { return r.try(r.r.Int8, f) }
+func (r ErrorWrapReceptor) Int16(f StructField) error   { return r.try(r.r.Int16, f) }
+func (r ErrorWrapReceptor) Int32(f StructField) error   { return r.try(r.r.Int32, f) }
+func (r ErrorWrapReceptor) Int64(f StructField) error   { return r.try(r.r.Int64, f) }
+func (r ErrorWrapReceptor) Uint(f StructField) error    { return r.try(r.r.Uint, f) }
+func (r ErrorWrapReceptor) Uint8(f StructField) error   { return r.try(r.r.Uint8, f) }
+func (r ErrorWrapReceptor) Uint16(f StructField) error  { return r.try(r.r.Uint16, f) }
+func (r ErrorWrapReceptor) Uint32(f StructField) error  { return r.try(r.r.Uint32, f) }
+func (r ErrorWrapReceptor) Uint64(f StructField) error  { return r.try(r.r.Uint64, f) }
+func (r ErrorWrapReceptor) Float32(f StructField) error { return r.try(r.r.Float32, f) }
+func (r ErrorWrapReceptor) Float64(f StructField) error { return r.try(r.r.Float64, f) }
+func (r ErrorWrapReceptor) String(f StructField) error  { return r.try(r.r.String, f) }
+func (r ErrorWrapReceptor) Any(f StructField) error     { return r.try(r.r.Any, f) }
@@ -0,0 +1,89 @@
+package internal
+
+import (
+	"errors"
+	"io"
+
+	"gopkg.in/yaml.v3"
+)
+
+// DecodeYAML decodes a YAML mapping into [Document].
+// The values are wrapped by [*DocumentNode] to hold the line and the column.
+func DecodeYAML(r io.Reader) (Document, error) {
+	var root yaml.Node
+	if err := yaml.NewDecoder(r).Decode(&root); err != nil {
+		if errors.Is(err, io.EOF) {
+			// empty
+			return Document{}, nil
+		}
+		return nil, JoinErrors(Errorf("decode yaml"), err)
+	}
+	v, err := convertYAMLNode(&root)
+	if err != nil {
+		return nil, err
+	}
+	switch v := unwrapDocumentNode(v).(type) {
+	case nil:
+		return Document{}, nil
+	case Document:
+		return v, nil
+	default:
+		return nil, Errorf("decode yaml: line %d, column %d: not a mapping", root.Line, root.Column)
+	}
+}
+
+func convertYAMLNode(n *yaml.Node) (any, error) {
+	switch n.Kind {
+	case yaml.DocumentNode:
+		if len(n.Content) == 0 {
+			return nil, nil
+		}
+		return convertYAMLNode(n.Content[0])
+	case yaml.AliasNode:
+		return convertYAMLNode(n.Alias)
+	case yaml.MappingNode:
+		d := Document{}
+		for i := 0; i+1 < len(n.Content); i += 2 {
+			k, v := n.Content[i], n.Content[i+1]
+			x, err := convertYAMLNode(v)
+			if err != nil {
+				return nil, err
+			}
+			d[k.Value] = x
+		}
+		return newYAMLDocumentNode(n, d), nil
+	case yaml.SequenceNode:
+		xs := make([]any, len(n.Content))
+		for i, c := range n.Content {
+			x, err := convertYAMLNode(c)
+			if err != nil {
+				return nil, err
+			}
+			xs[i] = x
+		}
+		return newYAMLDocumentNode(n, xs), nil
+	case yaml.ScalarNode:
+		switch n.ShortTag() {
+		case "!!null":
+			return newYAMLDocumentNode(n, nil), nil
+		case "!!bool", "!!int", "!!float":
+			var x any
+			if err := n.Decode(&x); err != nil {
+				return nil, JoinErrors(Errorf("decode yaml: line %d, column %d", n.Line, n.Column), err)
+			}
+			return newYAMLDocumentNode(n, x), nil
+		default:
+			return newYAMLDocumentNode(n, n.Value), nil
+		}
+	default:
+		return nil, Errorf("decode yaml: line %d, column %d: unknown node", n.Line, n.Column)
+	}
+}
+
+func newYAMLDocumentNode(n *yaml.Node, v any) *DocumentNode {
+	return &DocumentNode{
+		Value:  v,
+		Line:   n.Line,
+		Column: n.Column,
+	}
+}
@@ -0,0 +1,126 @@
+package internal_test
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDecodeYAML(t *testing.T) {
+	t.Run("empty", func(t *testing.T) {
+		doc, err := internal.DecodeYAML(strings.NewReader(""))
+		assert.Nil(t, err)
+		assert.Equal(t, internal.Document{}, doc)
+	})
+	t.Run("invalid", func(t *testing.T) {
+		_, err := internal.DecodeYAML(strings.NewReader("a: [1"))
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+	})
+	t.Run("not mapping", func(t *testing.T) {
+		_, err := internal.DecodeYAML(strings.NewReader("- 1"))
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+	})
+	t.Run("position", func(t *testing.T) {
+		doc, err := internal.DecodeYAML(strings.NewReader("a: 1\nb:\n  c: x\n"))
+		assert.Nil(t, err)
+		v, ok := doc.Lookup("b.c")
+		assert.True(t, ok)
+		assert.Equal(t, &internal.DocumentNode{
+			Value:  "x",
+			Line:   3,
+			Column: 6,
+		}, v)
+	})
+}
+
+func TestYAMLDocumentReceptor(t *testing.T) {
+	type DB struct {
+		Host string `name:"host"`
+		Port int    `name:"port" default:"5432"`
+	}
+	type T struct {
+		B      bool           `name:"b"`
+		I      int            `name:"i"`
+		Hex    int            `name:"hex"`
+		F      float64        `name:"f"`
+		S      string         `name:"s" default:"str"`
+		Null   int            `name:"null" default:"10"`
+		Slice  []int          `name:"slice"`
+		Map    map[string]int `name:"map"`
+		D      time.Duration  `name:"d"`
+		Alias  []int          `name:"alias"`
+		DB     DB             `name:"db"`
+		Ignore []int          `name:"-"`
+	}
+
+	t.Run("ok", func(t *testing.T) {
+		const input = `b: true
+i: 1
+hex: 0x10
+f: 1.5
+null: ~
+slice: &slice
+  - 1
+  - 2
+map: {a: 1, b: 2}
+d: 1m
+alias: *slice
+db:
+  host: localhost
+Ignore: [1]
+`
+		want := T{
+			B:     true,
+			I:     1,
+			Hex:   16,
+			F:     1.5,
+			S:     "str",
+			Null:  10,
+			Slice: []int{1, 2},
+			Map:   map[string]int{"a": 1, "b": 2},
+			D:     time.Minute,
+			Alias: []int{1, 2},
+			DB: DB{
+				Host: "localhost",
+				Port: 5432,
+			},
+		}
+
+		doc, err := internal.DecodeYAML(strings.NewReader(input))
+		assert.Nil(t, err)
+
+		var got T
+		r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+		assert.Nil(t, err)
+
+		typ, err := internal.NewType(got, "")
+		assert.Nil(t, err)
+
+		assert.Nil(t, typ.Accept(r))
+		assert.Equal(t, want, got)
+	})
+
+	t.Run("error", func(t *testing.T) {
+		const input = `db:
+  host: localhost
+  port: x
+`
+		doc, err := internal.DecodeYAML(strings.NewReader(input))
+		assert.Nil(t, err)
+
+		var got T
+		r, err := internal.DocumentReceptor(&got, doc, nil, nil, nil)
+		assert.Nil(t, err)
+
+		typ, err := internal.NewType(got, "")
+		assert.Nil(t, err)
+
+		err = typ.Accept(r)
+		assert.ErrorIs(t, err, internal.ErrStructConfig)
+		assert.ErrorContains(t, err, "line 3, column 9: DB.Port (db.port): ")
+		assert.ErrorContains(t, err, `parsing "x": invalid syntax`)
+	})
+}
@@ -0,0 +1,173 @@
+package structconfig
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/berquerant/structconfig/internal"
+	"github.com/spf13/pflag"
+)
+
+// Source names of [Origin].
+const (
+	SourceDefault    = "default"
+	SourceEnv        = "env"
+	SourceDotenv     = "dotenv"
+	SourceFlag       = "flag"
+	SourceJSON       = "json"
+	SourceYAML       = "yaml"
+	SourceTOML       = "toml"
+	SourceINI        = "ini"
+	SourceProperties = "properties"
+)
+
+// Origin is where the value of a field came from.
+type Origin struct {
+	// Source is the name of the source, e.g. [SourceEnv].
+	Source string
+	// Raw is the string value before the conversion, masked by [SecretMask] for the fields with "secret" tag.
+	Raw string
+	// Env is the environment variable name if Source is [SourceEnv] or [SourceDotenv].
+	Env string
+}
+
+func (o Origin) String() string {
+	if o.Env != "" {
+		return fmt.Sprintf("%s %s=%q", o.Source, o.Env, o.Raw)
+	}
+	return fmt.Sprintf("%s %q", o.Source, o.Raw)
+}
+
+// describe returns the source with the name of f in it, e.g. "env PORT", "flag --port".
+func (o Origin) describe(f StructField) string {
+	switch {
+	case o.Env != "":
+		return o.Source + " " + o.Env
+	case o.Source == SourceFlag:
+		if name, ok := f.Tag().Flag(); ok {
+			return o.Source + " --" + name
+		}
+	}
+	return o.Source
+}
+
+// Provenance is the origins of the field values by the Go field names, e.g. "DB.Host".
+type Provenance map[string]*Origin
+
+// recorder collects the origins of the values set by the From methods of [StructConfig].
+type recorder struct {
+	source  string
+	origins map[string]*Origin
+}
+
+func newRecorder() *recorder {
+	return &recorder{
+		origins: map[string]*Origin{},
+	}
+}
+
+// record records the origins of the fields given by the source if sc has a recorder.
+// origin reports false if the source has no value for the field.
+func (sc StructConfig[T]) record(source string, origin func(StructField) (*Origin, bool)) error {
+	if sc.recorder == nil {
+		return nil
+	}
+	typ, err := sc.newType()
+	if err != nil {
+		return err
+	}
+	sc.recorder.source = source
+	for _, f := range typ.Fields() {
+		if _, ok := f.Tag().Name(); !ok {
+			continue
+		}
+		if o, ok := origin(f); ok {
+			o.Source = source
+			o.Raw = internal.RedactValue(f, o.Raw)
+			sc.recorder.origins[f.Name()] = o
+		}
+	}
+	return nil
+}
+
+// envOrigins collects the origins of the environment variable values while [internal.EnvReceptor] sets them.
+type envOrigins map[string]*Origin
+
+func (m envOrigins) found(f StructField, v string, name EnvVar) {
+	m[f.Name()] = &Origin{
+		Raw: v,
+		Env: name.String(),
+	}
+}
+
+func (sc StructConfig[T]) recordEnv(source string, origins envOrigins) error {
+	return sc.record(source, func(f StructField) (*Origin, bool) {
+		o, ok := origins[f.Name()]
+		return o, ok
+	})
+}
+
+func (sc StructConfig[T]) recordDocument(source string, doc internal.Document) error {
+	return sc.record(source, func(f StructField) (*Origin, bool) {
+		key, ok := f.Tag().Key()
+		if !ok {
+			return nil, false
+		}
+		v, ok := doc.Lookup(key)
+		if !ok {
+			return nil, false
+		}
+		raw, err := internal.FormatDocumentValue(f, v)
+		if err != nil {
+			return nil, false
+		}
+		return &Origin{
+			Raw: raw,
+		}, true
+	})
+}
+
+func (sc StructConfig[T]) recordFlags(fs *pflag.FlagSet) error {
+	return sc.record(SourceFlag, func(f StructField) (*Origin, bool) {
+		name, ok := f.Tag().Flag()
+		if !ok {
+			return nil, false
+		}
+		x := fs.Lookup(name)
+		if x == nil || !x.Changed {
+			return nil, false
+		}
+		return &Origin{
+			Raw: x.Value.String(),
+		}, true
+	})
+}
+
+func defaultOrigin(f StructField) *Origin {
+	v, _ := f.Tag().Default()
+	return &Origin{
+		Source: SourceDefault,
+		Raw:    internal.RedactValue(f, v),
+	}
+}
+
+// layerOrigin returns the origin of the field f of v that is the result of the i-th generator of [Builder].
+// If no origin is recorded, the source is the name of the generator and the raw value is formatted from v.
+func layerOrigin[T any](i int, rec *recorder, f StructField, v *T) *Origin {
+	if o, ok := rec.origins[f.Name()]; ok {
+		x := *o
+		return &x
+	}
+	source := rec.source
+	if source == "" {
+		source = fmt.Sprintf("layer%d", i)
+	}
+	fv := reflect.ValueOf(v).Elem().FieldByIndex(f.Index())
+	if fv.Kind() == reflect.Pointer && !fv.IsNil() {
+		fv = fv.Elem()
+	}
+	return &Origin{
+		Source: source,
+		Raw:    internal.RedactValue(f, fmt.Sprint(fv)),
+	}
+}
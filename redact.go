@@ -0,0 +1,73 @@
+package structconfig
+
+import (
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+var (
+	_ slog.LogValuer = Redacted[struct{}]{}
+	_ fmt.Formatter  = Redacted[struct{}]{}
+	_ fmt.Stringer   = Redacted[struct{}]{}
+)
+
+// Redacted is a config whose values of the fields with "secret" tag are masked by [SecretMask]
+// when it is logged by [log/slog] or formatted by [fmt].
+type Redacted[T any] struct {
+	sc StructConfig[T]
+	v  *T
+}
+
+// Redact wraps v to log and format it without the secret values, e.g.
+//
+//	slog.Info("config", "config", sc.Redact(c))
+//	fmt.Printf("%v\n", sc.Redact(c))
+func (sc StructConfig[T]) Redact(v *T) Redacted[T] {
+	return Redacted[T]{
+		sc: sc,
+		v:  v,
+	}
+}
+
+// LogValue returns a group of the Go field names and the formatted values, see [StructConfig.Explain].
+func (r Redacted[T]) LogValue() slog.Value {
+	if r.v == nil {
+		return slog.AnyValue(nil)
+	}
+	xs, err := r.sc.Explain(r.v, nil)
+	if err != nil {
+		return slog.StringValue(fmt.Sprintf("!ERROR: %v", err))
+	}
+	attrs := make([]slog.Attr, len(xs))
+	for i, x := range xs {
+		attrs[i] = slog.String(x.Field, x.Value)
+	}
+	return slog.GroupValue(attrs...)
+}
+
+// String returns the Go field names and the formatted values like "{Host:localhost Token:******}".
+func (r Redacted[T]) String() string {
+	if r.v == nil {
+		return "<nil>"
+	}
+	xs, err := r.sc.Explain(r.v, nil)
+	if err != nil {
+		return fmt.Sprintf("%%!(ERROR: %v)", err)
+	}
+	ss := make([]string, len(xs))
+	for i, x := range xs {
+		ss[i] = x.Field + ":" + x.Value
+	}
+	return "{" + strings.Join(ss, " ") + "}"
+}
+
+// Format writes [Redacted.String], quoted for %q.
+func (r Redacted[T]) Format(f fmt.State, verb rune) {
+	if verb == 'q' {
+		fmt.Fprintf(f, "%q", r.String())
+		return
+	}
+	_, _ = io.WriteString(f, r.String())
+}
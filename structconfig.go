@@ -1,23 +1,52 @@
 package structconfig
 
 import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 
 	"github.com/berquerant/structconfig/internal"
 	"github.com/spf13/pflag"
 )
 
 const (
-	TagName    = internal.TagName
-	TagUsage   = internal.TagUsage
-	TagDefault = internal.TagDefault
-	TagShort   = internal.TagShort
+	TagName     = internal.TagName
+	TagUsage    = internal.TagUsage
+	TagDefault  = internal.TagDefault
+	TagShort    = internal.TagShort
+	TagSep      = internal.TagSep
+	TagLayout   = internal.TagLayout
+	TagEnv      = internal.TagEnv
+	TagFlag     = internal.TagFlag
+	TagKey      = internal.TagKey
+	TagRequired = internal.TagRequired
+	TagMin      = internal.TagMin
+	TagMax      = internal.TagMax
+	TagOneOf    = internal.TagOneOf
+	TagPattern  = internal.TagPattern
+	TagMinLen   = internal.TagMinLen
+	TagMaxLen   = internal.TagMaxLen
+	TagSecret   = internal.TagSecret
+
+	OneOfSeparator = internal.OneOfSeparator
+	SecretMask     = internal.SecretMask
+
+	DefaultRefPrefix = internal.DefaultRefPrefix
+
+	NameSeparator = internal.NameSeparator
+	DefaultSep    = internal.DefaultSep
+	DefaultLayout = internal.DefaultLayout
 )
 
 var (
 	ErrStructConfig     = internal.ErrStructConfig
 	ErrNotStruct        = internal.ErrNotStruct
 	ErrNotStructPointer = internal.ErrNotStructPointer
+	ErrRequired         = internal.ErrRequired
+	ErrValidation       = internal.ErrValidation
 )
 
 type (
@@ -34,21 +63,81 @@ type (
 	AnyReceptor     = internal.AnyReceptor
 	AnyCallbackFunc = func(StructField, string, func() reflect.Value) error
 	AnyEqualFunc    = func(left, right any) (bool, error)
+	EnvLookupFunc   = func(name string) (string, bool)
 	Unsigned        = internal.Unsigned
 	Supported       = internal.Supported
+	Registry        = internal.Registry
+	RegistryEntry   = internal.RegistryEntry
+	ValidationError = internal.ValidationError
+	Violation       = internal.Violation
+	MergeDecision   = internal.MergeDecision
+	EnvFileMode     = internal.EnvFileMode
+)
+
+const (
+	EnvFileNone   = internal.EnvFileNone
+	EnvFileAll    = internal.EnvFileAll
+	EnvFileSecret = internal.EnvFileSecret
+)
+
+const (
+	MergeDefault = internal.MergeDefault
+	MergeLeft    = internal.MergeLeft
+	MergeRight   = internal.MergeRight
 )
 
 func IsSupportedKind(k reflect.Kind) bool         { return internal.IsSupportedKind(k) }
 func NewType(v any, prefix string) (*Type, error) { return internal.NewType(v, prefix) }
 
-//go:generate go tool goconfig -configOption Option -option -output structconfig_config_generated.go -field "AnyCallback AnyCallbackFunc|AnyEqual AnyEqualFunc|Prefix string|Arguments []string"
+// NewRegistry returns a new empty [Registry].
+//
+// Pass it by [WithRegistry] to [New] and [NewMerger]
+// to convert and compare the values of the registered types before AnyCallback and AnyEqual.
+func NewRegistry() *Registry { return internal.NewRegistry() }
+
+// Register registers the conversion of T to r.
+//
+// parse converts the value of "default" tag, the environment variable or the command-line flag into T.
+// equal reports true if left equals right, it is used by [Merger] and can be nil.
+// The command-line flag of T is a string flag.
+//
+// The conversion is applied to the fields that are not converted according to the kind, e.g. structs, and
+// [time.Time], [encoding.TextUnmarshaler] and so on.
+// T of a supported kind, e.g. type Level int, is an error because the field of it is converted according to the kind.
+func Register[T any](r *Registry, parse func(string) (T, error), equal func(left, right T) bool) error {
+	entry := &RegistryEntry{
+		Parse: func(s string) (any, error) { return parse(s) },
+	}
+	if equal != nil {
+		entry.Equal = func(left, right any) (bool, error) {
+			return equal(left.(T), right.(T)), nil
+		}
+	}
+	return r.Register(reflect.TypeFor[T](), entry)
+}
+
+//go:generate go tool goconfig -configOption Option -option -output structconfig_config_generated.go -field "AnyCallback AnyCallbackFunc|AnyEqual AnyEqualFunc|Prefix string|Arguments []string|Registry *Registry|ConfigFile string|EnvLookup EnvLookupFunc|EnvPrefix string|Provenance *Provenance|EnvFile EnvFileMode|Expand bool"
 
 func newDefaultConfigBuilder() *ConfigBuilder {
 	return NewConfigBuilder().
 		AnyCallback(nil).
 		AnyEqual(nil).
 		Prefix("").
-		Arguments(nil)
+		Arguments(nil).
+		Registry(nil).
+		ConfigFile("").
+		EnvLookup(nil).
+		EnvPrefix("").
+		Provenance(nil).
+		EnvFile(EnvFileNone).
+		Expand(false)
+}
+
+// WithEnviron makes the environment variables resolved from environ instead of the process environment.
+// environ is a list of "key=value" like [os.Environ].
+// See [WithEnvLookup].
+func WithEnviron(environ []string) Option {
+	return WithEnvLookup(internal.EnvironLookup(environ))
 }
 
 type Merger[T any] struct {
@@ -60,30 +149,105 @@ type Merger[T any] struct {
 // AnyCallback parses "default" tag value and set it.
 // AnyEqual reports true if left equals right when kind of arguments are not supported.
 // Prefix adds a prefix to "default" tag name.
+// Registry converts and compares the values of the registered types before AnyCallback and AnyEqual.
+// Expand expands the variables in "default" tag values by EnvLookup, see [New].
 func NewMerger[T any](opt ...Option) *Merger[T] {
 	c := newDefaultConfigBuilder().Build()
 	c.Apply(opt...)
 
-	return &Merger[T]{
-		internal.NewMerger[T](
-			c.AnyCallback.Get(),
-			c.AnyEqual.Get(),
-			c.Prefix.Get(),
-		),
+	m := internal.NewMerger[T](
+		c.AnyCallback.Get(),
+		c.AnyEqual.Get(),
+		c.Prefix.Get(),
+		c.Registry.Get(),
+	)
+	if c.Expand.Get() {
+		m = m.WithExpand(internal.Expander(c.EnvLookup.Get()))
 	}
+	return &Merger[T]{m}
 }
 
 // Merge values based on the 'default' tag values.
 // For each field with 'name' and 'default' tags, if the right value is not the default, use it; if not, use the left value.
 // If that is also the default, set the default value. Return this instance.
+// A nil pointer field is not used because it means that the value is not given.
 func (m *Merger[T]) Merge(left, right T) (T, error) {
 	return m.Merger.Merge(left, right)
 }
 
+// MergeFunc is [Merger.Merge] that calls decide with the field and the value taken for it:
+// [MergeRight], [MergeLeft] or [MergeDefault]. decide can be nil.
+func (m *Merger[T]) MergeFunc(left, right T, decide func(StructField, MergeDecision)) (T, error) {
+	return m.Merger.MergeFunc(left, right, decide)
+}
+
+// MergeSetFunc is [Merger.MergeFunc] that uses the right value of the field if isSet reports true for it,
+// even if the value is the default value, e.g. --replicas=3 when "default" tag value is 3.
+// isSet tells the fields that the source of right actually set, can be nil.
+func (m *Merger[T]) MergeSetFunc(
+	left, right T,
+	isSet func(StructField) bool,
+	decide func(StructField, MergeDecision),
+) (T, error) {
+	return m.Merger.MergeSetFunc(left, right, isSet, decide)
+}
+
 // New returns a new StructConfig.
 //
+// A struct field that has a "name" tag and whose type is a struct with fields that have "name" tags is a nested struct.
+// The fields of it are configured instead of itself, and their names are joined with the name of it by '.',
+// e.g. "db.host" for the flag --db.host and the environment variable DB_HOST.
+// The fields of an embedded struct without a "name" tag are promoted like Go does;
+// ambiguous promoted fields with "name" tags are errors, and the others are dropped.
+//
+// "flag", "env" and "key" tags override the names derived from "name" tag for
+// the command-line flag, the environment variable and the key of configuration files, as is.
+// "-" disables the source for the field.
+//
+// "default" tag value can reference other fields like `default:"${.data_dir}/cache"`,
+// see [StructConfig.ResolveDefaultRefs].
+//
+// A field with `required:"true"` must be set by some source or have "default" tag in [Builder.Build],
+// see [StructConfig.CheckRequired].
+// The validation tags are also checked by [Builder.Build], see [StructConfig.Validate].
+//
+// The value of a field with `secret:"true"` is masked by [SecretMask] in [StructConfig.Explain], [StructConfig.Dump],
+// [Provenance], the flag default value shown by [StructConfig.SetFlags], the errors and the violations.
+// Use [StructConfig.Redact] to log or format the config.
+//
+// A pointer to a supported kind, e.g. *int, is an optional field:
+// it is left nil when no value is given and allocated when some value is given.
+//
+// A slice of a supported kind, e.g. []string, is split by "sep" tag value (default: ",")
+// for "default" tag values, environment variables and command-line flags.
+// The command-line flag of it is the slice flag of pflag, e.g. [pflag.FlagSet.StringSlice],
+// if "sep" tag value is ",".
+//
+// A map from string to a supported kind, e.g. map[string]int, is parsed like "k1=v1,k2=v2",
+// "sep" tag value separates the elements.
+// The command-line flag of it is the map flag of pflag, e.g. [pflag.FlagSet.StringToString],
+// if "sep" tag value is ",".
+//
+// [time.Duration] is parsed by [time.ParseDuration], e.g. "5s".
+// [time.Time] is parsed by [time.Parse] with "layout" tag value (default: [time.RFC3339]).
+// *[time.Location] is loaded by [time.LoadLocation], e.g. "Asia/Tokyo".
+//
+// A type whose pointer implements [encoding.TextUnmarshaler], e.g. [log/slog.Level], is parsed by it.
+// The command-line flag of it shows the default value by [encoding.TextMarshaler] if implemented.
+//
 // AnyCallback parses "default" tag value and set it.
 // Prefix adds a prefix to "name", "short", "default" and "usage" tag name.
+// Registry converts the values of the registered types before AnyCallback.
+// EnvLookup retrieves the environment variables instead of [os.LookupEnv].
+// EnvPrefix adds a prefix to the environment variable names, e.g. "myapp_" makes "port" MYAPP_PORT,
+// and the flag usage shows the environment variable name.
+// EnvFile enables the environment variables with "_FILE" suffix, see [StructConfig.FromEnv]:
+// [EnvFileAll] for all fields, [EnvFileSecret] for the fields with "secret" tag.
+// Expand expands the variables like the shell in "default" tag values and environment variable values by EnvLookup:
+// $VAR, ${VAR}, ${VAR:-word} (word if VAR is unset or empty), ${VAR-word} (word if VAR is unset) and $$ (literal $),
+// e.g. `default:"${XDG_RUNTIME_DIR:-/tmp}/app.sock"`.
+// The contents of the files by EnvFile are not expanded.
+// Give the same Expand and EnvLookup to [NewMerger] when merging without [Builder].
 func New[T any](opt ...Option) *StructConfig[T] {
 	c := newDefaultConfigBuilder().Build()
 	c.Apply(opt...)
@@ -91,12 +255,38 @@ func New[T any](opt ...Option) *StructConfig[T] {
 	return &StructConfig[T]{
 		anyCallback: c.AnyCallback.Get(),
 		prefix:      c.Prefix.Get(),
+		registry:    c.Registry.Get(),
+		envLookup:   c.EnvLookup.Get(),
+		envPrefix:   c.EnvPrefix.Get(),
+		envFile:     c.EnvFile.Get(),
+		expand:      c.Expand.Get(),
 	}
 }
 
 type StructConfig[T any] struct {
 	anyCallback AnyCallbackFunc
 	prefix      string
+	registry    *Registry
+	envLookup   EnvLookupFunc
+	envPrefix   string
+	envFile     EnvFileMode
+	expand      bool
+	recorder    *recorder
+}
+
+func (sc StructConfig[T]) lookupEnv(name string) (string, bool) {
+	if sc.envLookup == nil {
+		return os.LookupEnv(name)
+	}
+	return sc.envLookup(name)
+}
+
+// expander returns the expansion of the variables by [WithExpand], nil if disabled.
+func (sc StructConfig[T]) expander() func(string) (string, error) {
+	if !sc.expand {
+		return nil
+	}
+	return internal.Expander(sc.lookupEnv)
 }
 
 func (sc StructConfig[T]) newType() (*Type, error) {
@@ -112,39 +302,314 @@ func (sc StructConfig[T]) from(r Receptor) error {
 	return typ.Accept(r)
 }
 
+// CheckRequired reports all fields with "required" tag whose values in v are zero in one error.
+// The error wraps [ErrRequired] and lists the flag names and the environment variable names of the fields.
+//
+// It cannot tell an explicit zero, e.g. --replicas=0, from an absent value;
+// [Builder.Build] checks whether some source set the fields instead.
+func (sc StructConfig[T]) CheckRequired(v *T) error {
+	return sc.checkRequired(v, nil)
+}
+
+// checkRequired is [StructConfig.CheckRequired] by the fields that some source set, see [internal.CheckRequired].
+func (sc StructConfig[T]) checkRequired(v *T, isSet func(StructField) bool) error {
+	typ, err := sc.newType()
+	if err != nil {
+		return err
+	}
+	return internal.CheckRequired(v, typ, sc.envPrefix, isSet)
+}
+
+// Validate validates v by the validation tags:
+//   - min, max: the bounds of numbers and [time.Duration], e.g. `min:"1" max:"65535"`
+//   - oneof: the allowed values separated by [OneOfSeparator], e.g. `oneof:"debug|info|warn"`
+//   - pattern: the regular expression that strings should match, e.g. `pattern:"^[a-z]+$"`
+//   - minlen, maxlen: the bounds of the length of strings, slices and maps
+//
+// All violations are reported by one [*ValidationError] that wraps [ErrValidation].
+// Nil pointers are not validated.
+// [Violation.Source] is empty because v has no origins; [Builder.Build] names the sources.
+func (sc StructConfig[T]) Validate(v *T) error {
+	return sc.validate(v, nil)
+}
+
+// validate is [StructConfig.Validate] that names the sources of the values in the violations by provenance.
+func (sc StructConfig[T]) validate(v *T, provenance Provenance) error {
+	var source func(StructField) string
+	if provenance != nil {
+		source = func(f StructField) string {
+			if o, ok := provenance[f.Name()]; ok {
+				return o.describe(f)
+			}
+			return ""
+		}
+	}
+	r, err := internal.NewValidationReceptor(v, sc.envPrefix, source)
+	if err != nil {
+		return err
+	}
+	if err := sc.from(r); err != nil {
+		return err
+	}
+	return r.Err()
+}
+
 // FromDefault sets "default" tag values to v.
+// The fields whose "default" tag values reference other fields are left zero, see [StructConfig.ResolveDefaultRefs].
 func (sc StructConfig[T]) FromDefault(v *T) error {
-	r, err := internal.DefaultReceptor(v, sc.anyCallback)
+	r, err := internal.DefaultReceptor(v, sc.anyCallback, sc.registry, sc.expander())
 	if err != nil {
 		return err
 	}
 	return sc.from(r)
 }
 
+// ResolveDefaultRefs sets "default" tag values that reference other fields to the zero or empty fields of v.
+// [Builder.Build] sets them to the fields that no source set instead, so an explicit zero value is kept.
+//
+// ${.name} in "default" tag value is replaced by the value of the field whose "name" tag value is name,
+// e.g. `default:"${.listen_host}:9090"`; the value is formatted like "default" tag value.
+// The fields are resolved in dependency order.
+// Unknown names and cycles of the references like "a -> b -> a" are errors.
+// The leading '.' distinguishes the references from the environment variables like ${HOME}, see [WithExpand].
+// [Builder.Build] calls it after merging all values.
+func (sc StructConfig[T]) ResolveDefaultRefs(v *T) error {
+	_, err := sc.resolveDefaultRefs(v, nil)
+	return err
+}
+
+// resolveDefaultRefs is [StructConfig.ResolveDefaultRefs] for the fields that no source set,
+// and returns the set values, see [internal.ResolveDefaultRefs].
+func (sc StructConfig[T]) resolveDefaultRefs(v *T, isSet func(StructField) bool) (map[string]string, error) {
+	typ, err := sc.newType()
+	if err != nil {
+		return nil, err
+	}
+	return internal.ResolveDefaultRefs(v, typ, sc.anyCallback, sc.registry, sc.expander(), isSet)
+}
+
 // FromEnv sets environment variable values to v.
 //
 // Environment variable name will be
 //
-//	NewEnvVar("name tag value").String()
+//	NewEnvVar("env prefix" + "name tag value").String()
 //
 // All '.' and '-' will be replaced with '_', making it all uppsercase.
+// The env prefix is given by [WithEnvPrefix].
+// The value is retrieved by [WithEnvLookup] or [WithEnviron], [os.LookupEnv] by default.
+//
+// If [WithEnvFile] is given and the environment variable is absent,
+// the value is read from the file named by the environment variable with "_FILE" suffix, e.g. DB_PASSWORD_FILE,
+// without the trailing newline. An unreadable file is an error.
 func (sc StructConfig[T]) FromEnv(v *T) error {
-	r, err := internal.EnvReceptor(v, sc.anyCallback)
+	origins := envOrigins{}
+	r, err := internal.EnvReceptor(
+		v, sc.envPrefix, sc.envFile, sc.lookupEnv, sc.anyCallback, sc.registry, sc.expander(), origins.found,
+	)
 	if err != nil {
 		return err
 	}
-	return sc.from(r)
+	if err := sc.from(r); err != nil {
+		return err
+	}
+	return sc.recordEnv(SourceEnv, origins)
+}
+
+// FromDotenv sets values to v from a dotenv file read from r.
+//
+// The variable names are the same as [StructConfig.FromEnv],
+// but the values are taken only from r; the process environment is not modified.
+// ${VAR} and $VAR in the values are expanded by the variables defined earlier in r or by the environment variables
+// retrieved like [StructConfig.FromEnv].
+func (sc StructConfig[T]) FromDotenv(v *T, r io.Reader) error {
+	env, err := internal.DecodeDotenv(r, sc.lookupEnv)
+	if err != nil {
+		return err
+	}
+	lookup := env.Lookup
+	if sc.expand {
+		// the values are already expanded by DecodeDotenv
+		lookup = func(name string) (string, bool) {
+			v, ok := env.Lookup(name)
+			return internal.ExpandEscape(v), ok
+		}
+	}
+	origins := envOrigins{}
+	x, err := internal.EnvReceptor(
+		v, sc.envPrefix, sc.envFile, lookup, sc.anyCallback, sc.registry, sc.expander(), origins.found,
+	)
+	if err != nil {
+		return err
+	}
+	if err := sc.from(x); err != nil {
+		return err
+	}
+	return sc.recordEnv(SourceDotenv, origins)
+}
+
+// FromDotenvFile sets values to v from a dotenv file.
+// See [StructConfig.FromDotenv].
+func (sc StructConfig[T]) FromDotenvFile(v *T, path string) error {
+	return sc.fromFile(v, path, sc.FromDotenv)
+}
+
+// FromJSON sets values to v from a JSON object read from r.
+//
+// JSON key is from "name" tag value.
+// The name of a nested struct field like "db.host" is found by the key "db.host" or the nested object {"db":{"host":...}}.
+// Arrays are for slices and objects are for maps.
+// If the key is missing, "default" tag value is used.
+func (sc StructConfig[T]) FromJSON(v *T, r io.Reader) error {
+	doc, err := internal.DecodeJSON(r)
+	if err != nil {
+		return err
+	}
+	return sc.fromDocument(v, doc, SourceJSON)
+}
+
+// FromJSONFile sets values to v from a JSON file.
+// See [StructConfig.FromJSON].
+func (sc StructConfig[T]) FromJSONFile(v *T, path string) error {
+	return sc.fromFile(v, path, sc.FromJSON)
+}
+
+// FromYAML sets values to v from a YAML mapping read from r.
+//
+// YAML key is from "name" tag value, the same as [StructConfig.FromJSON].
+// Errors for bad values include the line and the column of the value,
+// the Go field name and the "name" tag value.
+func (sc StructConfig[T]) FromYAML(v *T, r io.Reader) error {
+	doc, err := internal.DecodeYAML(r)
+	if err != nil {
+		return err
+	}
+	return sc.fromDocument(v, doc, SourceYAML)
+}
+
+// FromYAMLFile sets values to v from a YAML file.
+// See [StructConfig.FromYAML].
+func (sc StructConfig[T]) FromYAMLFile(v *T, path string) error {
+	return sc.fromFile(v, path, sc.FromYAML)
+}
+
+// FromTOML sets values to v from a TOML document read from r.
+//
+// TOML key is from "name" tag value, the same as [StructConfig.FromJSON].
+// Tables are for nested structs and maps, e.g. the key "host" in the table [db] is for "db.host".
+// Errors for bad values include the line and the column of the value,
+// the Go field name and the "name" tag value.
+func (sc StructConfig[T]) FromTOML(v *T, r io.Reader) error {
+	doc, err := internal.DecodeTOML(r)
+	if err != nil {
+		return err
+	}
+	return sc.fromDocument(v, doc, SourceTOML)
+}
+
+// FromTOMLFile sets values to v from a TOML file.
+// See [StructConfig.FromTOML].
+func (sc StructConfig[T]) FromTOMLFile(v *T, path string) error {
+	return sc.fromFile(v, path, sc.FromTOML)
+}
+
+// FromINI sets values to v from an INI file read from r.
+//
+// The key "host" in the section [db] is for the "name" tag value "db.host".
+// Errors for bad values include the line and the column of the value,
+// the Go field name and the "name" tag value.
+func (sc StructConfig[T]) FromINI(v *T, r io.Reader) error {
+	doc, err := internal.DecodeINI(r)
+	if err != nil {
+		return err
+	}
+	return sc.fromDocument(v, doc, SourceINI)
+}
+
+// FromINIFile sets values to v from an INI file.
+// See [StructConfig.FromINI].
+func (sc StructConfig[T]) FromINIFile(v *T, path string) error {
+	return sc.fromFile(v, path, sc.FromINI)
+}
+
+// FromProperties sets values to v from a Java .properties file read from r.
+//
+// The key "db.host" is for the "name" tag value "db.host".
+// Errors for bad values include the line and the column of the value,
+// the Go field name and the "name" tag value.
+func (sc StructConfig[T]) FromProperties(v *T, r io.Reader) error {
+	doc, err := internal.DecodeProperties(r)
+	if err != nil {
+		return err
+	}
+	return sc.fromDocument(v, doc, SourceProperties)
+}
+
+// FromPropertiesFile sets values to v from a Java .properties file.
+// See [StructConfig.FromProperties].
+func (sc StructConfig[T]) FromPropertiesFile(v *T, path string) error {
+	return sc.fromFile(v, path, sc.FromProperties)
+}
+
+// FromFile sets values to v from a configuration file.
+// The format is determined by the extension of path:
+//
+//   - .json: [StructConfig.FromJSONFile]
+//   - .yaml, .yml: [StructConfig.FromYAMLFile]
+//   - .toml: [StructConfig.FromTOMLFile]
+//   - .ini: [StructConfig.FromINIFile]
+//   - .properties: [StructConfig.FromPropertiesFile]
+//   - .env: [StructConfig.FromDotenvFile]
+func (sc StructConfig[T]) FromFile(v *T, path string) error {
+	switch ext := filepath.Ext(path); ext {
+	case ".json":
+		return sc.FromJSONFile(v, path)
+	case ".yaml", ".yml":
+		return sc.FromYAMLFile(v, path)
+	case ".toml":
+		return sc.FromTOMLFile(v, path)
+	case ".ini":
+		return sc.FromINIFile(v, path)
+	case ".properties":
+		return sc.FromPropertiesFile(v, path)
+	case ".env":
+		return sc.FromDotenvFile(v, path)
+	default:
+		return internal.Errorf("%s: unsupported config file format %q", path, ext)
+	}
+}
+
+func (sc StructConfig[T]) fromFile(v *T, path string, from func(*T, io.Reader) error) error {
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	return from(v, f)
+}
+
+func (sc StructConfig[T]) fromDocument(v *T, doc internal.Document, source string) error {
+	r, err := internal.DocumentReceptor(v, doc, sc.anyCallback, sc.registry, sc.expander())
+	if err != nil {
+		return err
+	}
+	if err := sc.from(r); err != nil {
+		return err
+	}
+	return sc.recordDocument(source, doc)
 }
 
 // FromFlags sets values to v from command-line flags.
 //
 // Flag name is from "name" tag value.
 func (sc StructConfig[T]) FromFlags(v *T, fs *pflag.FlagSet) error {
-	r, err := internal.PFlagGetReceptor(v, fs, sc.anyCallback)
+	r, err := internal.PFlagGetReceptor(v, fs, sc.anyCallback, sc.registry)
 	if err != nil {
 		return err
 	}
-	return sc.from(r)
+	if err := sc.from(r); err != nil {
+		return err
+	}
+	return sc.recordFlags(fs)
 }
 
 // SetFlags sets command-line flags.
@@ -152,8 +617,46 @@ func (sc StructConfig[T]) FromFlags(v *T, fs *pflag.FlagSet) error {
 // Flag name is from "name" tag value.
 // Flag shorthand is from "short" tag value.
 // Flag default value is from "default" tag value.
-// Flag usage is from "usage" tag value.
+// Flag usage is from "usage" tag value,
+// followed by the environment variable name like "(env MYAPP_PORT)" if [WithEnvPrefix] is given.
+// Every field with "name" tag has the environment variable, but the name is shown only with the prefix
+// to keep the usages of the programs that do not opt in as they are;
+// the prefix makes the name differ from the flag name, so it is worth showing.
+// The default value of the flag with "secret" tag is shown as [SecretMask].
 func (sc StructConfig[T]) SetFlags(fs *pflag.FlagSet) error {
-	r := internal.PFlagSetReceptor(fs)
-	return sc.from(r)
+	r := internal.PFlagSetReceptor(fs, sc.registry, sc.expander())
+	if err := sc.from(r); err != nil {
+		return err
+	}
+	typ, err := sc.newType()
+	if err != nil {
+		return err
+	}
+	for _, f := range typ.Fields() {
+		name, ok := f.Tag().Flag()
+		if !ok {
+			continue
+		}
+		x := fs.Lookup(name)
+		if x == nil {
+			continue
+		}
+		if d, ok := f.Tag().Default(); ok && f.Tag().Secret() && d != "" {
+			x.DefValue = SecretMask
+		}
+		if sc.envPrefix == "" {
+			continue
+		}
+		if env, ok := sc.EnvVar(f); ok {
+			x.Usage = strings.TrimSpace(fmt.Sprintf("%s (env %s)", x.Usage, env))
+		}
+	}
+	return nil
+}
+
+// EnvVar returns the environment variable of f.
+// It is "env" tag value if exists, otherwise from "name" tag value and [WithEnvPrefix].
+// ok is false if f has no "name" tag or "env" tag value is "-".
+func (sc StructConfig[T]) EnvVar(f StructField) (EnvVar, bool) {
+	return internal.FieldEnvVar(f, sc.envPrefix)
 }
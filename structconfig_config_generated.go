@@ -1,4 +1,4 @@
-// Code generated by "goconfig -configOption Option -option -output structconfig_config_generated.go -field AnyCallback AnyCallbackFunc|AnyEqual AnyEqualFunc|Prefix string|Arguments []string"; DO NOT EDIT.
+// Code generated by "goconfig -configOption Option -option -output structconfig_config_generated.go -field AnyCallback AnyCallbackFunc|AnyEqual AnyEqualFunc|Prefix string|Arguments []string|Registry *Registry|ConfigFile string|EnvLookup EnvLookupFunc|EnvPrefix string|Provenance *Provenance|EnvFile EnvFileMode|Expand bool"; DO NOT EDIT.
 
 package structconfig
 
@@ -35,12 +35,26 @@ type Config struct {
 	AnyEqual    *ConfigItem[AnyEqualFunc]
 	Prefix      *ConfigItem[string]
 	Arguments   *ConfigItem[[]string]
+	Registry    *ConfigItem[*Registry]
+	ConfigFile  *ConfigItem[string]
+	EnvLookup   *ConfigItem[EnvLookupFunc]
+	EnvPrefix   *ConfigItem[string]
+	Provenance  *ConfigItem[*Provenance]
+	EnvFile     *ConfigItem[EnvFileMode]
+	Expand      *ConfigItem[bool]
 }
 type ConfigBuilder struct {
 	anyCallback AnyCallbackFunc
 	anyEqual    AnyEqualFunc
 	prefix      string
 	arguments   []string
+	registry    *Registry
+	configFile  string
+	envLookup   EnvLookupFunc
+	envPrefix   string
+	provenance  *Provenance
+	envFile     EnvFileMode
+	expand      bool
 }
 
 func (s *ConfigBuilder) AnyCallback(v AnyCallbackFunc) *ConfigBuilder {
@@ -59,12 +73,47 @@ func (s *ConfigBuilder) Arguments(v []string) *ConfigBuilder {
 	s.arguments = v
 	return s
 }
+func (s *ConfigBuilder) Registry(v *Registry) *ConfigBuilder {
+	s.registry = v
+	return s
+}
+func (s *ConfigBuilder) ConfigFile(v string) *ConfigBuilder {
+	s.configFile = v
+	return s
+}
+func (s *ConfigBuilder) EnvLookup(v EnvLookupFunc) *ConfigBuilder {
+	s.envLookup = v
+	return s
+}
+func (s *ConfigBuilder) EnvPrefix(v string) *ConfigBuilder {
+	s.envPrefix = v
+	return s
+}
+func (s *ConfigBuilder) Provenance(v *Provenance) *ConfigBuilder {
+	s.provenance = v
+	return s
+}
+func (s *ConfigBuilder) EnvFile(v EnvFileMode) *ConfigBuilder {
+	s.envFile = v
+	return s
+}
+func (s *ConfigBuilder) Expand(v bool) *ConfigBuilder {
+	s.expand = v
+	return s
+}
 func (s *ConfigBuilder) Build() *Config {
 	return &Config{
 		AnyCallback: NewConfigItem(s.anyCallback),
 		AnyEqual:    NewConfigItem(s.anyEqual),
 		Prefix:      NewConfigItem(s.prefix),
 		Arguments:   NewConfigItem(s.arguments),
+		Registry:    NewConfigItem(s.registry),
+		ConfigFile:  NewConfigItem(s.configFile),
+		EnvLookup:   NewConfigItem(s.envLookup),
+		EnvPrefix:   NewConfigItem(s.envPrefix),
+		Provenance:  NewConfigItem(s.provenance),
+		EnvFile:     NewConfigItem(s.envFile),
+		Expand:      NewConfigItem(s.expand),
 	}
 }
 
@@ -97,3 +146,38 @@ func WithArguments(v []string) Option {
 		c.Arguments.Set(v)
 	}
 }
+func WithRegistry(v *Registry) Option {
+	return func(c *Config) {
+		c.Registry.Set(v)
+	}
+}
+func WithConfigFile(v string) Option {
+	return func(c *Config) {
+		c.ConfigFile.Set(v)
+	}
+}
+func WithEnvLookup(v EnvLookupFunc) Option {
+	return func(c *Config) {
+		c.EnvLookup.Set(v)
+	}
+}
+func WithEnvPrefix(v string) Option {
+	return func(c *Config) {
+		c.EnvPrefix.Set(v)
+	}
+}
+func WithProvenance(v *Provenance) Option {
+	return func(c *Config) {
+		c.Provenance.Set(v)
+	}
+}
+func WithEnvFile(v EnvFileMode) Option {
+	return func(c *Config) {
+		c.EnvFile.Set(v)
+	}
+}
+func WithExpand(v bool) Option {
+	return func(c *Config) {
+		c.Expand.Set(v)
+	}
+}
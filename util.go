@@ -1,21 +1,32 @@
 package structconfig
 
 import (
+	"errors"
 	"os"
 
+	"github.com/berquerant/structconfig/internal"
 	"github.com/spf13/pflag"
 )
 
 // NewConfigWithMerge generates a Config by taking into account default values,
-// environment variables, and command-line arguments.
+// a configuration file, environment variables, and command-line arguments.
 //
-// It overrides the default values with values obtained from environment variables
+// It overrides the default values with values obtained from the configuration file specified with [WithConfigFile],
+// further overrides them with values obtained from environment variables
 // and further overrides them with the values from command-line arguments.
+// The values that are given explicitly override even if they are the default values, see [Builder.Build].
+// The configuration file is read by [StructConfig.FromFile].
 // The command-line arguments are obtained by calling [StructConfig.SetFlags]
 // on the fs and then parsing with [pflag.FlagSet.Parse].
 //
 // In this process, the values to be parsed are those specified with [WithArguments].
 // If none are specified, it uses [os.Args].
+// The environment variables are retrieved by [WithEnvLookup] or [WithEnviron] if specified,
+// otherwise by the sc. So are the prefix of them by [WithEnvPrefix], the files by [WithEnvFile]
+// and the expansion of the variables by [WithExpand].
+//
+// If [WithProvenance] is specified, the origins of the field values are stored into it,
+// see [Builder.BuildWithProvenance].
 func NewConfigWithMerge[T any](
 	sc *StructConfig[T],
 	merger *Merger[T],
@@ -25,7 +36,34 @@ func NewConfigWithMerge[T any](
 	c := newDefaultConfigBuilder().Build()
 	c.Apply(opt...)
 
-	return NewBuilder(sc, merger).
+	if c.EnvLookup.IsModified() || c.EnvPrefix.IsModified() || c.EnvFile.IsModified() || c.Expand.IsModified() {
+		x := *sc
+		if c.EnvLookup.IsModified() {
+			x.envLookup = c.EnvLookup.Get()
+		}
+		if c.EnvPrefix.IsModified() {
+			x.envPrefix = c.EnvPrefix.Get()
+		}
+		if c.EnvFile.IsModified() {
+			x.envFile = c.EnvFile.Get()
+		}
+		if c.Expand.IsModified() {
+			x.expand = c.Expand.Get()
+		}
+		sc = &x
+	}
+
+	b := NewBuilder(sc, merger)
+	if path := c.ConfigFile.Get(); path != "" {
+		b.Add(func(sc *StructConfig[T]) (*T, error) {
+			var t T
+			if err := sc.FromFile(&t, path); err != nil {
+				return nil, err
+			}
+			return &t, nil
+		})
+	}
+	b.
 		Add(func(sc *StructConfig[T]) (*T, error) {
 			var t T
 			if err := sc.FromEnv(&t); err != nil {
@@ -44,13 +82,40 @@ func NewConfigWithMerge[T any](
 				arguments = os.Args
 			}
 			if err := fs.Parse(arguments); err != nil {
-				return nil, err
+				return nil, sc.redactFlagError(err)
 			}
 			var t T
 			if err := sc.FromFlags(&t, fs); err != nil {
 				return nil, err
 			}
 			return &t, nil
-		}).
-		Build()
+		})
+
+	if p := c.Provenance.Get(); p != nil {
+		r, provenance, err := b.BuildWithProvenance()
+		if err != nil {
+			return nil, err
+		}
+		*p = provenance
+		return r, nil
+	}
+	return b.Build()
+}
+
+// redactFlagError masks the invalid value of the flag with "secret" tag in err from [pflag.FlagSet.Parse].
+func (sc StructConfig[T]) redactFlagError(err error) error {
+	var verr *pflag.InvalidValueError
+	if !errors.As(err, &verr) {
+		return err
+	}
+	typ, terr := sc.newType()
+	if terr != nil {
+		return err
+	}
+	for _, f := range typ.Fields() {
+		if name, ok := f.Tag().Flag(); ok && name == verr.GetFlag().Name {
+			return internal.RedactError(f, verr.GetValue(), err)
+		}
+	}
+	return err
 }